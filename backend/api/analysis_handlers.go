@@ -0,0 +1,139 @@
+package api
+
+import (
+	alg "backend/internal/algorithm"
+	"backend/internal/analysis"
+	"backend/model"
+	"encoding/json"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+func (h *Handler) HandleGetStats(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if err := json.NewEncoder(w).Encode(h.stats); err != nil {
+		http.Error(w, "Failed to encode stats", http.StatusInternalServerError)
+		log.Printf("Error encoding stats: %v", err)
+	}
+}
+
+func (h *Handler) HandleImportance(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	elementName := strings.TrimSpace(strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/analysis/importance"), "/"))
+	report := h.importanceReport()
+
+	if elementName != "" {
+		if _, exists := h.elements[elementName]; !exists {
+			h.writeElementNotFound(w, elementName)
+			return
+		}
+
+		result := map[string]interface{}{
+			"element":    elementName,
+			"reachable":  h.reachable[elementName],
+			"importance": report.Elements[elementName],
+			"dependents": report.Dependents(elementName),
+			"dominators": report.Dominators(elementName),
+		}
+		if err := json.NewEncoder(w).Encode(result); err != nil {
+			http.Error(w, "Failed to encode importance", http.StatusInternalServerError)
+			log.Printf("Error encoding importance: %v", err)
+		}
+		return
+	}
+
+	sortBy := r.URL.Query().Get("sort")
+	if sortBy != "" && sortBy != "dependents" && sortBy != "centrality" {
+		http.Error(w, "Invalid sort (use dependents or centrality)", http.StatusBadRequest)
+		return
+	}
+
+	ranked := report.Ranked(sortBy)
+	if limitParam := r.URL.Query().Get("limit"); limitParam != "" {
+		if limit, err := strconv.Atoi(limitParam); err == nil && limit > 0 && limit < len(ranked) {
+			ranked = ranked[:limit]
+		}
+	}
+
+	if err := json.NewEncoder(w).Encode(ranked); err != nil {
+		http.Error(w, "Failed to encode importance", http.StatusInternalServerError)
+		log.Printf("Error encoding importance: %v", err)
+	}
+}
+
+func (h *Handler) HandlePrerequisites(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	path := strings.TrimPrefix(r.URL.Path, "/api/elements/")
+	elementName := strings.TrimSpace(strings.TrimSuffix(path, "/prerequisites"))
+
+	if _, exists := h.elements[elementName]; !exists {
+		h.writeElementNotFound(w, elementName)
+		return
+	}
+
+	prerequisites := h.importanceReport().Prerequisites(h.graph, elementName)
+	if err := json.NewEncoder(w).Encode(prerequisites); err != nil {
+		http.Error(w, "Failed to encode prerequisites", http.StatusInternalServerError)
+		log.Printf("Error encoding prerequisites: %v", err)
+	}
+}
+
+func (h *Handler) HandleComplexity(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	elementName := strings.TrimSpace(strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/complexity"), "/"))
+	complexities := h.complexityReport()
+
+	if elementName != "" {
+		complexity, exists := complexities[elementName]
+		if _, known := h.elements[elementName]; !known || !exists {
+			h.writeElementNotFound(w, elementName)
+			return
+		}
+
+		// path terpendek dari bidirectional search, buat dibandingkan dengan
+		// kedalaman pohon dari analisis graf. Panjangnya jumlah langkah di
+		// path, -1 kalo tidak ketemu.
+		shortestPath, visited := alg.FindShortestPath(h.elements, elementName)
+		shortestPathLength := -1
+		if shortestPath == nil {
+			shortestPath = []model.Node{}
+		} else {
+			shortestPathLength = len(shortestPath) - 1
+		}
+
+		result := map[string]interface{}{
+			"complexity":         complexity,
+			"shortestPathLength": shortestPathLength,
+			"shortestPath":       shortestPath,
+			"visitedNodes":       visited,
+		}
+		if err := json.NewEncoder(w).Encode(result); err != nil {
+			http.Error(w, "Failed to encode complexity", http.StatusInternalServerError)
+			log.Printf("Error encoding complexity: %v", err)
+		}
+		return
+	}
+
+	sortBy := r.URL.Query().Get("sort")
+	switch sortBy {
+	case "", "depth", "combinations", "trees", "recipes":
+	default:
+		http.Error(w, "Invalid sort (use depth, combinations, trees or recipes)", http.StatusBadRequest)
+		return
+	}
+
+	ranked := analysis.RankComplexity(complexities, sortBy)
+	if limitParam := r.URL.Query().Get("limit"); limitParam != "" {
+		if limit, err := strconv.Atoi(limitParam); err == nil && limit > 0 && limit < len(ranked) {
+			ranked = ranked[:limit]
+		}
+	}
+
+	if err := json.NewEncoder(w).Encode(ranked); err != nil {
+		http.Error(w, "Failed to encode complexity", http.StatusInternalServerError)
+		log.Printf("Error encoding complexity: %v", err)
+	}
+}
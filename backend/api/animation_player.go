@@ -0,0 +1,463 @@
+package api
+
+import (
+	alg "backend/internal/algorithm"
+	"backend/internal/recordings"
+	"backend/utils"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+const (
+	minAnimationSpeed = 0.1
+	maxAnimationSpeed = 20.0
+)
+
+// Perintah yang bisa dikirim client lewat WebSocket
+const (
+	actionPause  = "pause"
+	actionResume = "resume"
+	actionStep   = "step"
+	actionBack   = "back"
+	actionSeek   = "seek"
+	actionSpeed  = "speed"
+	actionStart  = "start"
+	actionPing   = "ping"
+)
+
+// animationCommand adalah perintah client yang sudah dibaca reader
+type animationCommand struct {
+	AnimationCommand
+	// diisi reader kalo pesan tidak bisa dibaca
+	err error
+}
+
+// animationPlayer memutar event satu pencarian ke satu koneksi. Semua penulisan
+// ke conn dilakukan dari goroutine run, goroutine lain hanya membaca perintah.
+type animationPlayer struct {
+	h    *Handler
+	conn *websocket.Conn
+
+	element   string
+	algorithm string
+	// id rekaman yang sedang diputar ulang, kosong kalo pencarian live
+	recording      string
+	datasetVersion string
+
+	// room yang ikut menerima semua pesan, nil kalo bukan presenter
+	room *animationRoom
+
+	events       *eventLog
+	cancelSearch context.CancelFunc
+	resultChan   chan animationResult
+
+	// jumlah step yang sudah ditampilkan di client
+	position  int
+	paused    bool
+	speed     float64
+	completed bool
+}
+
+func newAnimationPlayer(h *Handler, conn *websocket.Conn) *animationPlayer {
+	return &animationPlayer{h: h, conn: conn, speed: 1}
+}
+
+// start membatalkan pencarian yang sedang jalan (kalo ada) dan memulai yang
+// baru. Kalo record true, event dan hasilnya disimpan sebagai rekaman setelah
+// pencarian selesai.
+func (p *animationPlayer) start(element, algorithm string, record bool) error {
+	p.stopSearch()
+
+	search := animationSearch(algorithm)
+	ctx, cancel := context.WithCancel(context.Background())
+	p.reset(element, algorithm, "", p.h.datasetVersion)
+	p.cancelSearch = cancel
+
+	if err := p.writeMetadata(record); err != nil {
+		return err
+	}
+
+	monitor := alg.NewMonitor(ctx, nil)
+	monitor.SetEventHandler(p.events.add)
+
+	events, resultChan := p.events, p.resultChan
+	go func() {
+		defer events.finish()
+		defer func() {
+			if r := recover(); r != nil {
+				log.Printf("ERROR: Animation search for %s panicked: %v", element, r)
+				resultChan <- animationResult{err: fmt.Sprintf("search failed: %v", r)}
+			}
+		}()
+
+		startTime := time.Now()
+		paths, visited := search(p.h.elements, element, monitor)
+		result := animationResult{paths: paths, visited: visited, elapsed: time.Since(startTime)}
+
+		// pencarian yang dibatalkan tidak lengkap, tidak usah disimpan
+		if record && !monitor.Cancelled() {
+			result.recording, result.recordErr = p.h.saveRecording(element, algorithm, events, result)
+		}
+		resultChan <- result
+	}()
+
+	return nil
+}
+
+// replay memutar ulang rekaman dengan cara yang sama seperti pencarian live
+func (p *animationPlayer) replay(id string, rec *recordings.Recording) error {
+	p.stopSearch()
+	p.cancelSearch = nil
+	p.reset(rec.Element, rec.Algorithm, id, rec.DatasetVersion)
+
+	p.events.events = rec.Events
+	p.events.truncated = rec.Truncated
+	p.events.finished = true
+	p.resultChan <- animationResult{
+		paths:   rec.Paths,
+		visited: rec.NodesVisited,
+		elapsed: time.Duration(rec.TimeElapsed) * time.Millisecond,
+	}
+
+	log.Printf("DEBUG: Replaying recording %s (%d steps)", id, len(rec.Events))
+	return p.writeMetadata(false)
+}
+
+func (p *animationPlayer) reset(element, algorithm, recording, datasetVersion string) {
+	p.element = element
+	p.algorithm = algorithm
+	p.recording = recording
+	p.datasetVersion = datasetVersion
+	p.events = newEventLog()
+	p.resultChan = make(chan animationResult, 1)
+	p.position = 0
+	p.completed = false
+}
+
+func (p *animationPlayer) writeMetadata(record bool) error {
+	return p.write(MetadataMessage{
+		Type:           messageMetadata,
+		Protocol:       AnimationSubprotocol,
+		Version:        AnimationProtocolVersion,
+		Algorithm:      p.algorithm,
+		Element:        p.element,
+		DatasetVersion: p.datasetVersion,
+		Record:         record,
+		Recording:      p.recording,
+	})
+}
+
+func (p *animationPlayer) stopSearch() {
+	if p.cancelSearch != nil {
+		p.cancelSearch()
+	}
+}
+
+// run adalah loop utama: memutar step sesuai speed, menjalankan perintah
+// client dan mengirim ping, sampai client putus
+func (p *animationPlayer) run() {
+	p.conn.SetReadLimit(animationReadLimit)
+	p.conn.SetReadDeadline(time.Now().Add(animationPongWait))
+	p.conn.SetPongHandler(func(string) error {
+		return p.conn.SetReadDeadline(time.Now().Add(animationPongWait))
+	})
+
+	commands := make(chan animationCommand)
+	stop := make(chan struct{})
+	defer close(stop)
+	go p.readCommands(commands, stop)
+
+	pingTicker := time.NewTicker(animationPingPeriod)
+	defer pingTicker.Stop()
+
+	for {
+		var tick <-chan time.Time
+		var notify <-chan struct{}
+
+		if !p.paused {
+			_, available, done := p.events.get(p.position)
+			switch {
+			case available:
+				tick = time.After(p.delay())
+			case !done:
+				notify = p.events.notify
+			case !p.completed:
+				if err := p.complete(); err != nil {
+					log.Printf("ERROR: Failed to send animation result: %v", err)
+					return
+				}
+				continue
+			}
+		}
+
+		select {
+		case command, ok := <-commands:
+			if !ok {
+				log.Printf("DEBUG: Animation client for %s disconnected after %d steps", p.element, p.position)
+				return
+			}
+			if err := p.handleCommand(command); err != nil {
+				log.Printf("ERROR: Failed to handle animation command %q: %v", command.Action, err)
+				return
+			}
+		case <-tick:
+			if err := p.forward(); err != nil {
+				log.Printf("ERROR: Failed to send animation step: %v", err)
+				return
+			}
+		case <-notify:
+		case <-pingTicker.C:
+			if err := p.conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(animationWriteWait)); err != nil {
+				log.Printf("DEBUG: Failed to ping animation client for %s: %v", p.element, err)
+				return
+			}
+		}
+	}
+}
+
+func (p *animationPlayer) readCommands(commands chan<- animationCommand, stop <-chan struct{}) {
+	defer close(commands)
+	for {
+		_, message, err := p.conn.ReadMessage()
+		if err != nil {
+			return
+		}
+		// pesan apa pun dari client juga tanda koneksi masih hidup
+		p.conn.SetReadDeadline(time.Now().Add(animationPongWait))
+
+		var command animationCommand
+		if err := json.Unmarshal(message, &command.AnimationCommand); err != nil {
+			command.err = fmt.Errorf("invalid message: %v", err)
+		}
+		select {
+		case commands <- command:
+		case <-stop:
+			return
+		}
+	}
+}
+
+func (p *animationPlayer) handleCommand(command animationCommand) error {
+	if command.err != nil {
+		return p.sendError(ErrorInvalidMessage, command.err.Error(), nil)
+	}
+
+	switch command.Action {
+	case actionPause:
+		p.paused = true
+	case actionResume:
+		p.paused = false
+	case actionStep:
+		p.paused = true
+		if _, available, done := p.events.get(p.position); available {
+			if err := p.forward(); err != nil {
+				return err
+			}
+		} else if done && !p.completed {
+			if err := p.complete(); err != nil {
+				return err
+			}
+		}
+	case actionBack:
+		p.paused = true
+		if err := p.backward(); err != nil {
+			return err
+		}
+	case actionSeek:
+		if err := p.seek(command.Step); err != nil {
+			return err
+		}
+	case actionSpeed:
+		if command.Speed <= 0 {
+			return p.sendError(ErrorInvalidSpeed, "speed must be positive", nil)
+		}
+		p.speed = command.Speed
+		if p.speed < minAnimationSpeed {
+			p.speed = minAnimationSpeed
+		}
+		if p.speed > maxAnimationSpeed {
+			p.speed = maxAnimationSpeed
+		}
+	case actionStart:
+		if command.Recording != "" {
+			rec, err := p.h.recordings.Load(command.Recording)
+			if errors.Is(err, recordings.ErrNotFound) || errors.Is(err, recordings.ErrInvalidID) {
+				return p.sendError(ErrorRecordingNotFound, fmt.Sprintf("Recording not found: %q", command.Recording), nil)
+			}
+			if err != nil {
+				return p.sendError(ErrorRecordingFailed, err.Error(), nil)
+			}
+			if err := p.replay(command.Recording, rec); err != nil {
+				return err
+			}
+			break
+		}
+		if _, exists := p.h.elements[command.Element]; !exists {
+			suggestions := make([]string, 0, 5)
+			for _, match := range utils.RankNames(command.Element, p.h.names, 5) {
+				suggestions = append(suggestions, match.Name)
+			}
+			return p.sendError(ErrorElementNotFound, fmt.Sprintf("Element not found: %q", command.Element), suggestions)
+		}
+		algorithm := command.Algorithm
+		if algorithm == "" {
+			algorithm = p.algorithm
+		}
+		if animationSearch(algorithm) == nil {
+			return p.sendError(ErrorUnknownAlgorithm, fmt.Sprintf("Unknown algorithm: %q", algorithm), nil)
+		}
+		log.Printf("DEBUG: Restarting animation with %s for %s", algorithm, command.Element)
+		if err := p.start(command.Element, algorithm, command.Record); err != nil {
+			return err
+		}
+	case actionPing:
+		return p.write(PongMessage{Type: messagePong, Time: time.Now().UnixMilli()})
+	default:
+		return p.sendError(ErrorUnknownAction, fmt.Sprintf("Unknown action: %q", command.Action), nil)
+	}
+
+	return p.sendState()
+}
+
+// forward mengirim step berikutnya, event-nya harus sudah ada
+func (p *animationPlayer) forward() error {
+	event, _, _ := p.events.get(p.position)
+	p.position++
+	return p.write(p.h.animationEventStep(event, p.position, p.events.len()))
+}
+
+// backward membatalkan step terakhir, client harus menghapus efek step itu
+func (p *animationPlayer) backward() error {
+	if p.position == 0 {
+		return nil
+	}
+
+	event, _, _ := p.events.get(p.position - 1)
+	step := p.h.animationEventStep(event, p.position, p.events.len())
+	step.Type = messageUndo
+	p.position--
+	return p.write(step)
+}
+
+// seek maju atau mundur ke step target tanpa jeda. Tidak bisa melewati event
+// yang belum dihasilkan algoritma.
+func (p *animationPlayer) seek(target int) error {
+	if target < 0 {
+		target = 0
+	}
+	for p.position > target {
+		if err := p.backward(); err != nil {
+			return err
+		}
+	}
+	for p.position < target {
+		if _, available, _ := p.events.get(p.position); !available {
+			break
+		}
+		if err := p.forward(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// complete mengirim hasil pencarian setelah semua step diputar
+func (p *animationPlayer) complete() error {
+	result := <-p.resultChan
+	p.completed = true
+
+	p.events.mu.Lock()
+	truncated := p.events.truncated
+	p.events.mu.Unlock()
+
+	if result.err != "" {
+		if err := p.sendError(ErrorSearchFailed, result.err, nil); err != nil {
+			return err
+		}
+	}
+	if result.recordErr != nil {
+		if err := p.sendError(ErrorRecordingFailed, result.recordErr.Error(), nil); err != nil {
+			return err
+		}
+	}
+
+	trees := p.h.pathsToTrees(result.paths, p.element, 1)
+	if err := p.write(ResultMessage{
+		Type:      messageResult,
+		Trees:     trees,
+		Found:     len(trees) > 0,
+		Truncated: truncated,
+		Error:     result.err,
+		Recording: result.recording,
+	}); err != nil {
+		return err
+	}
+
+	log.Printf("DEBUG: Animation complete for %s, sent %d steps (truncated: %v)", p.element, p.position, truncated)
+	return p.write(CompleteMessage{
+		Type:         messageComplete,
+		NodesVisited: result.visited,
+		TimeElapsed:  result.elapsed.Milliseconds(),
+		TotalSteps:   p.position,
+	})
+}
+
+// sendState memberi tahu client keadaan player setelah perintahnya dijalankan
+func (p *animationPlayer) sendState() error {
+	status := "playing"
+	if p.paused {
+		status = "paused"
+	}
+
+	p.events.mu.Lock()
+	availableSteps := len(p.events.events)
+	searchDone := p.events.finished
+	p.events.mu.Unlock()
+
+	viewers := 0
+	if p.room != nil {
+		viewers = p.room.viewerCount()
+	}
+
+	return p.write(StateMessage{
+		Type:           messageState,
+		Status:         status,
+		Element:        p.element,
+		Algorithm:      p.algorithm,
+		StepIndex:      p.position,
+		AvailableSteps: availableSteps,
+		SearchDone:     searchDone,
+		Completed:      p.completed,
+		Speed:          p.speed,
+		Viewers:        viewers,
+	})
+}
+
+func (p *animationPlayer) sendError(code, message string, suggestions []string) error {
+	return p.write(ErrorMessage{
+		Type:        messageError,
+		Code:        code,
+		Message:     message,
+		Suggestions: suggestions,
+	})
+}
+
+// write mengirim satu pesan dengan batas waktu, supaya client yang lambat atau
+// macet tidak menahan handler selamanya
+func (p *animationPlayer) write(message interface{}) error {
+	if p.room != nil {
+		p.room.broadcast(message)
+	}
+	p.conn.SetWriteDeadline(time.Now().Add(animationWriteWait))
+	return p.conn.WriteJSON(message)
+}
+
+func (p *animationPlayer) delay() time.Duration {
+	return time.Duration(float64(animationStepDelay) / p.speed)
+}
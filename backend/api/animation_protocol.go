@@ -0,0 +1,188 @@
+package api
+
+import (
+	alg "backend/internal/algorithm"
+	"time"
+)
+
+// Protokol WebSocket animasi (/api/animation-ws/{element}?algorithm=bfs|dfs|bidirectional&paused=true)
+//
+// Negosiasi: client mengirim subprotocol AnimationSubprotocol di header
+// Sec-WebSocket-Protocol. Kalo client menawarkan subprotocol tapi tidak ada
+// yang didukung, koneksi ditutup dengan close code 1002 (protocol error).
+// Client lama yang tidak menawarkan subprotocol tetap dilayani dengan versi
+// terbaru. Versi yang dipakai selalu ada di pesan "metadata".
+//
+// Query ?record=true menyimpan pencarian sebagai rekaman setelah selesai (id-nya
+// ada di pesan "result"), ?recording=<id> memutar ulang rekaman tanpa mencari
+// lagi. Rekaman diputar dengan pesan yang sama persis seperti pencarian live.
+//
+// Room (/api/animation-ws/room/{id}): satu presenter (?role=presenter&element=...)
+// menjalankan pencarian dan mengontrol pemutaran, viewer menerima pesan yang
+// sama persis. Viewer yang join belakangan langsung dikirimi metadata dan
+// semua step sampai posisi sekarang. Perintah dari viewer selain "ping" ditolak
+// dengan error read_only, viewer yang terlalu lambat diputus dengan close code
+// 1008.
+//
+// Setiap pesan adalah satu objek JSON dengan field "type".
+//
+// Server ke client:
+//
+//	metadata  MetadataMessage, dikirim setiap pencarian dimulai
+//	event     AnimationStep, satu langkah eksplorasi algoritma
+//	undo      AnimationStep, step yang harus dihapus client (back/seek mundur)
+//	state     StateMessage, keadaan player setelah perintah client dijalankan
+//	result    ResultMessage, pohon resep hasil pencarian
+//	complete  CompleteMessage, semua step sudah diputar
+//	pong      PongMessage, balasan perintah "ping"
+//	error     ErrorMessage, perintah ditolak, koneksi tetap jalan
+//	room      RoomMessage, hanya untuk viewer room
+//
+// Client ke server (AnimationCommand):
+//
+//	{"action": "pause"} / {"action": "resume"}
+//	{"action": "step"} / {"action": "back"}     maju/mundur satu step, sekalian pause
+//	{"action": "seek", "step": 120}             lompat ke step tertentu
+//	{"action": "speed", "speed": 2}             kelipatan kecepatan, 0.1 sampai 20
+//	{"action": "start", "element": "Brick", "algorithm": "dfs", "record": true}
+//	{"action": "start", "recording": "<id dari GET /api/recordings>"}
+//	{"action": "ping"}                          untuk client yang tidak bisa kirim ping frame
+//
+// Server mengirim ping frame setiap animationPingPeriod dan menutup koneksi
+// kalo tidak ada pong atau pesan lain selama animationPongWait.
+
+const (
+	AnimationProtocolVersion = 1
+	AnimationSubprotocol     = "alchemy-animation.v1"
+
+	animationWriteWait  = 10 * time.Second
+	animationPongWait   = 60 * time.Second
+	animationPingPeriod = animationPongWait * 9 / 10
+	// perintah client kecil, pesan lebih besar dari ini pasti salah
+	animationReadLimit = 4096
+)
+
+// Jenis pesan server
+const (
+	messageMetadata = "metadata"
+	messageEvent    = "event"
+	messageUndo     = "undo"
+	messageState    = "state"
+	messageResult   = "result"
+	messageComplete = "complete"
+	messagePong     = "pong"
+	messageError    = "error"
+	messageRoom     = "room"
+)
+
+// Kode di ErrorMessage
+const (
+	ErrorInvalidMessage    = "invalid_message"
+	ErrorUnknownAction     = "unknown_action"
+	ErrorInvalidSpeed      = "invalid_speed"
+	ErrorElementNotFound   = "element_not_found"
+	ErrorUnknownAlgorithm  = "unknown_algorithm"
+	ErrorSearchFailed      = "search_failed"
+	ErrorRecordingNotFound = "recording_not_found"
+	ErrorRecordingFailed   = "recording_failed"
+	ErrorReadOnly          = "read_only"
+)
+
+// AnimationCommand adalah pesan dari client
+type AnimationCommand struct {
+	Action    string  `json:"action"`
+	Step      int     `json:"step,omitempty"`
+	Speed     float64 `json:"speed,omitempty"`
+	Element   string  `json:"element,omitempty"`
+	Algorithm string  `json:"algorithm,omitempty"`
+	Record    bool    `json:"record,omitempty"`
+	Recording string  `json:"recording,omitempty"`
+}
+
+type MetadataMessage struct {
+	Type           string `json:"type"`
+	Protocol       string `json:"protocol"`
+	Version        int    `json:"version"`
+	Algorithm      string `json:"algorithm"`
+	Element        string `json:"element"`
+	DatasetVersion string `json:"datasetVersion"`
+	// pencarian ini akan disimpan sebagai rekaman
+	Record bool `json:"record,omitempty"`
+	// id rekaman yang sedang diputar ulang
+	Recording string `json:"recording,omitempty"`
+}
+
+type AnimationNode struct {
+	Name      string `json:"name"`
+	ImagePath string `json:"imagePath"`
+}
+
+type AnimationLink struct {
+	Source string `json:"source"`
+	Target string `json:"target"`
+}
+
+// AnimationStep adalah satu event pencarian. Link hanya ada kalo event punya
+// parent, arahnya dari elemen yang sedang diekspansi ke elemen yang ditemukan.
+type AnimationStep struct {
+	Type        string          `json:"type"`
+	StepIndex   int             `json:"stepIndex"`
+	TotalSteps  int             `json:"totalSteps"`
+	Event       alg.SearchEvent `json:"event"`
+	Node        AnimationNode   `json:"node"`
+	Link        *AnimationLink  `json:"link,omitempty"`
+	IsBaseNode  bool            `json:"isBaseNode"`
+	IsCompleted bool            `json:"isCompleted"`
+}
+
+type StateMessage struct {
+	Type      string `json:"type"`
+	Status    string `json:"status"`
+	Element   string `json:"element"`
+	Algorithm string `json:"algorithm"`
+	StepIndex int    `json:"stepIndex"`
+	// jumlah step yang sudah dihasilkan algoritma, bisa masih bertambah
+	AvailableSteps int     `json:"availableSteps"`
+	SearchDone     bool    `json:"searchDone"`
+	Completed      bool    `json:"completed"`
+	Speed          float64 `json:"speed"`
+	// jumlah viewer kalo presenter di room
+	Viewers int `json:"viewers,omitempty"`
+}
+
+type ResultMessage struct {
+	Type      string                   `json:"type"`
+	Trees     []map[string]interface{} `json:"trees"`
+	Found     bool                     `json:"found"`
+	Truncated bool                     `json:"truncated"`
+	Error     string                   `json:"error,omitempty"`
+	// id rekaman yang baru disimpan
+	Recording string `json:"recording,omitempty"`
+}
+
+type CompleteMessage struct {
+	Type         string `json:"type"`
+	NodesVisited int    `json:"nodesVisited"`
+	TimeElapsed  int64  `json:"timeElapsed"`
+	TotalSteps   int    `json:"totalSteps"`
+}
+
+type PongMessage struct {
+	Type string `json:"type"`
+	Time int64  `json:"time"`
+}
+
+// RoomMessage dikirim ke viewer room setiap ada yang join atau keluar
+type RoomMessage struct {
+	Type      string `json:"type"`
+	Room      string `json:"room"`
+	Viewers   int    `json:"viewers"`
+	Presenter bool   `json:"presenter"`
+}
+
+type ErrorMessage struct {
+	Type        string   `json:"type"`
+	Code        string   `json:"code"`
+	Message     string   `json:"message"`
+	Suggestions []string `json:"suggestions,omitempty"`
+}
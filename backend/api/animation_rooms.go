@@ -0,0 +1,387 @@
+package api
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+const (
+	animationRoomPrefix = "/api/animation-ws/room/"
+	maxRoomIDLength     = 64
+	// cukup untuk seek dari awal sampai akhir sekaligus, viewer yang antreannya
+	// penuh dianggap terlalu lambat dan diputus
+	roomViewerBuffer = maxAnimationEvents + 64
+)
+
+// animationHub menyimpan room yang sedang aktif. Room dibuat saat ada yang
+// join dan dihapus saat presenter dan semua viewer sudah keluar.
+type animationHub struct {
+	mu    sync.Mutex
+	rooms map[string]*animationRoom
+}
+
+func newAnimationHub() *animationHub {
+	return &animationHub{rooms: make(map[string]*animationRoom)}
+}
+
+// roomLocked mengambil atau membuat room, hub.mu harus sudah dikunci
+func (hub *animationHub) roomLocked(id string) *animationRoom {
+	room, exists := hub.rooms[id]
+	if !exists {
+		room = &animationRoom{id: id, hub: hub, viewers: make(map[*roomViewer]struct{})}
+		hub.rooms[id] = room
+	}
+	return room
+}
+
+// claimPresenter mendaftarkan presenter room, false kalo room sudah punya presenter
+func (hub *animationHub) claimPresenter(id string) (*animationRoom, bool) {
+	hub.mu.Lock()
+	defer hub.mu.Unlock()
+
+	room := hub.roomLocked(id)
+	room.mu.Lock()
+	defer room.mu.Unlock()
+	if room.presenter {
+		return nil, false
+	}
+	room.presenter = true
+	room.broadcastRoomLocked()
+	return room, true
+}
+
+// join mendaftarkan viewer ke room
+func (hub *animationHub) join(id string, conn *websocket.Conn) (*animationRoom, *roomViewer) {
+	hub.mu.Lock()
+	defer hub.mu.Unlock()
+
+	room := hub.roomLocked(id)
+	return room, room.join(conn)
+}
+
+func (hub *animationHub) removeIfEmpty(room *animationRoom) {
+	hub.mu.Lock()
+	defer hub.mu.Unlock()
+
+	room.mu.Lock()
+	empty := !room.presenter && len(room.viewers) == 0
+	room.mu.Unlock()
+
+	if empty && hub.rooms[room.id] == room {
+		delete(hub.rooms, room.id)
+		log.Printf("DEBUG: Animation room %s closed", room.id)
+	}
+}
+
+// animationRoom meneruskan pesan presenter ke semua viewer. Room juga
+// menyimpan pesan yang membentuk tampilan sekarang supaya viewer yang baru
+// join bisa langsung sinkron.
+type animationRoom struct {
+	id  string
+	hub *animationHub
+
+	mu        sync.Mutex
+	presenter bool
+	viewers   map[*roomViewer]struct{}
+
+	// metadata pencarian sekarang, step sampai posisi sekarang (undo membuang
+	// step terakhir), result/complete, dan state terakhir
+	metadata []byte
+	steps    [][]byte
+	tail     [][]byte
+	state    []byte
+}
+
+type roomViewer struct {
+	conn *websocket.Conn
+	send chan []byte
+	// true kalo diputus karena terlalu lambat, sisa antrean tidak dikirim lagi
+	dropped atomic.Bool
+}
+
+func (room *animationRoom) releasePresenter() {
+	room.mu.Lock()
+	room.presenter = false
+	room.broadcastRoomLocked()
+	room.mu.Unlock()
+	room.hub.removeIfEmpty(room)
+}
+
+func (room *animationRoom) viewerCount() int {
+	room.mu.Lock()
+	defer room.mu.Unlock()
+	return len(room.viewers)
+}
+
+// broadcast dipanggil presenter untuk setiap pesan yang dia terima. Pesan
+// yang hanya untuk presenter (error, pong) tidak diteruskan.
+func (room *animationRoom) broadcast(message interface{}) {
+	var stepType string
+	switch m := message.(type) {
+	case MetadataMessage, ResultMessage, CompleteMessage, StateMessage:
+	case AnimationStep:
+		stepType = m.Type
+	default:
+		return
+	}
+
+	data, err := json.Marshal(message)
+	if err != nil {
+		log.Printf("ERROR: Failed to encode room message: %v", err)
+		return
+	}
+
+	room.mu.Lock()
+	defer room.mu.Unlock()
+
+	switch message.(type) {
+	case MetadataMessage:
+		room.metadata = data
+		room.steps = nil
+		room.tail = nil
+		room.state = nil
+	case AnimationStep:
+		if stepType == messageUndo {
+			if len(room.steps) > 0 {
+				room.steps = room.steps[:len(room.steps)-1]
+			}
+		} else {
+			room.steps = append(room.steps, data)
+		}
+	case ResultMessage, CompleteMessage:
+		room.tail = append(room.tail, data)
+	case StateMessage:
+		room.state = data
+	}
+
+	for viewer := range room.viewers {
+		room.sendLocked(viewer, data)
+	}
+}
+
+// sendLocked mengantrekan pesan untuk satu viewer tanpa menunggu. Viewer yang
+// antreannya penuh diputus supaya tidak memperlambat room.
+func (room *animationRoom) sendLocked(viewer *roomViewer, data []byte) {
+	select {
+	case viewer.send <- data:
+	default:
+		log.Printf("WARNING: Dropping slow viewer from animation room %s", room.id)
+		viewer.dropped.Store(true)
+		room.removeLocked(viewer)
+		room.broadcastRoomLocked()
+	}
+}
+
+func (room *animationRoom) removeLocked(viewer *roomViewer) {
+	if _, exists := room.viewers[viewer]; !exists {
+		return
+	}
+	delete(room.viewers, viewer)
+	close(viewer.send)
+}
+
+// broadcastRoomLocked memberi tahu viewer jumlah penonton dan ada tidaknya presenter
+func (room *animationRoom) broadcastRoomLocked() {
+	data, err := json.Marshal(room.infoLocked())
+	if err != nil {
+		return
+	}
+	for viewer := range room.viewers {
+		select {
+		case viewer.send <- data:
+		default:
+			// viewer yang penuh akan diputus di broadcast berikutnya
+		}
+	}
+}
+
+func (room *animationRoom) infoLocked() RoomMessage {
+	return RoomMessage{
+		Type:      messageRoom,
+		Room:      room.id,
+		Viewers:   len(room.viewers),
+		Presenter: room.presenter,
+	}
+}
+
+// join mendaftarkan viewer dan mengantrekan pesan untuk menyamakan tampilannya
+// dengan presenter. Snapshot dan pendaftaran dilakukan di bawah lock yang sama,
+// jadi tidak ada pesan yang terlewat atau terkirim dua kali. Antrean viewer
+// dibuat cukup untuk seluruh history (rekaman bisa lebih panjang dari
+// maxAnimationEvents) dan tetap dikirim tanpa menunggu, jadi join tidak
+// pernah menahan lock room dan hub.
+func (room *animationRoom) join(conn *websocket.Conn) *roomViewer {
+	room.mu.Lock()
+	defer room.mu.Unlock()
+
+	var history [][]byte
+	if room.metadata != nil {
+		history = make([][]byte, 0, len(room.steps)+len(room.tail)+2)
+		history = append(history, room.metadata)
+		history = append(history, room.steps...)
+		history = append(history, room.tail...)
+		if room.state != nil {
+			history = append(history, room.state)
+		}
+	}
+
+	viewer := &roomViewer{conn: conn, send: make(chan []byte, len(history)+roomViewerBuffer)}
+	room.viewers[viewer] = struct{}{}
+
+	for _, data := range history {
+		if viewer.dropped.Load() {
+			break
+		}
+		room.sendLocked(viewer, data)
+	}
+	room.broadcastRoomLocked()
+
+	return viewer
+}
+
+func (room *animationRoom) leave(viewer *roomViewer) {
+	room.mu.Lock()
+	if _, exists := room.viewers[viewer]; exists {
+		room.removeLocked(viewer)
+		room.broadcastRoomLocked()
+	}
+	room.mu.Unlock()
+	room.hub.removeIfEmpty(room)
+}
+
+// reply mengantrekan pesan untuk satu viewer saja, misal pong atau error
+func (room *animationRoom) reply(viewer *roomViewer, message interface{}) {
+	data, err := json.Marshal(message)
+	if err != nil {
+		return
+	}
+
+	room.mu.Lock()
+	defer room.mu.Unlock()
+	if _, exists := room.viewers[viewer]; exists {
+		room.sendLocked(viewer, data)
+	}
+}
+
+// HandleAnimationRoom melayani /api/animation-ws/room/{id}. Dengan
+// ?role=presenter&element=...&algorithm=... koneksi ini menjalankan pencarian
+// dan mengontrol pemutaran seperti WebSocket animasi biasa (hanya satu
+// presenter per room). Tanpa role, koneksi jadi viewer yang hanya menerima
+// pesan yang sama dengan presenter.
+func (h *Handler) HandleAnimationRoom(w http.ResponseWriter, r *http.Request) {
+	roomID := strings.Trim(strings.TrimPrefix(r.URL.Path, animationRoomPrefix), "/")
+	if !validRoomID(roomID) {
+		http.Error(w, "Invalid room id", http.StatusBadRequest)
+		return
+	}
+
+	if r.URL.Query().Get("role") == "presenter" {
+		room, ok := h.rooms.claimPresenter(roomID)
+		if !ok {
+			http.Error(w, "Room already has a presenter", http.StatusConflict)
+			return
+		}
+		defer room.releasePresenter()
+
+		log.Printf("DEBUG: Presenter joined animation room %s", roomID)
+		h.serveAnimation(w, r, r.URL.Query().Get("element"), room)
+		return
+	}
+
+	conn, ok := upgradeAnimation(w, r)
+	if !ok {
+		return
+	}
+	defer conn.Close()
+
+	room, viewer := h.rooms.join(roomID, conn)
+	log.Printf("DEBUG: Viewer joined animation room %s", roomID)
+
+	writerDone := make(chan struct{})
+	go func() {
+		defer close(writerDone)
+		viewer.writeLoop()
+	}()
+
+	conn.SetReadLimit(animationReadLimit)
+	conn.SetReadDeadline(time.Now().Add(animationPongWait))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(animationPongWait))
+	})
+
+	for {
+		_, message, err := conn.ReadMessage()
+		if err != nil {
+			break
+		}
+		conn.SetReadDeadline(time.Now().Add(animationPongWait))
+
+		var command AnimationCommand
+		if err := json.Unmarshal(message, &command); err != nil {
+			room.reply(viewer, ErrorMessage{Type: messageError, Code: ErrorInvalidMessage, Message: "invalid message: " + err.Error()})
+			continue
+		}
+		if command.Action == actionPing {
+			room.reply(viewer, PongMessage{Type: messagePong, Time: time.Now().UnixMilli()})
+			continue
+		}
+		room.reply(viewer, ErrorMessage{Type: messageError, Code: ErrorReadOnly, Message: "viewers cannot control the animation"})
+	}
+
+	room.leave(viewer)
+	<-writerDone
+	log.Printf("DEBUG: Viewer left animation room %s", roomID)
+}
+
+// writeLoop mengirim antrean viewer dan ping sampai antreannya ditutup
+func (viewer *roomViewer) writeLoop() {
+	pingTicker := time.NewTicker(animationPingPeriod)
+	defer pingTicker.Stop()
+
+	for {
+		select {
+		case data, ok := <-viewer.send:
+			if viewer.dropped.Load() {
+				viewer.conn.WriteControl(websocket.CloseMessage,
+					websocket.FormatCloseMessage(websocket.ClosePolicyViolation, "viewer too slow"),
+					time.Now().Add(animationWriteWait))
+				// reader ikut berhenti kalo koneksi ditutup
+				viewer.conn.Close()
+				return
+			}
+			if !ok {
+				viewer.conn.Close()
+				return
+			}
+			viewer.conn.SetWriteDeadline(time.Now().Add(animationWriteWait))
+			if err := viewer.conn.WriteMessage(websocket.TextMessage, data); err != nil {
+				viewer.conn.Close()
+				return
+			}
+		case <-pingTicker.C:
+			if err := viewer.conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(animationWriteWait)); err != nil {
+				viewer.conn.Close()
+				return
+			}
+		}
+	}
+}
+
+func validRoomID(id string) bool {
+	if id == "" || len(id) > maxRoomIDLength {
+		return false
+	}
+	for _, r := range id {
+		if !((r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') || r == '-' || r == '_') {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,45 @@
+package api
+
+import (
+	"testing"
+	"time"
+)
+
+// viewer yang join room dengan history lebih panjang dari roomViewerBuffer
+// (misal rekaman panjang) tidak boleh bikin join macet sambil memegang lock
+func TestRoomJoinWithLongHistory(t *testing.T) {
+	hub := newAnimationHub()
+	room, _ := hub.claimPresenter("long")
+
+	steps := roomViewerBuffer + 100
+	room.broadcast(MetadataMessage{Type: messageMetadata, Element: "Steam"})
+	for i := 0; i < steps; i++ {
+		room.broadcast(AnimationStep{Type: messageEvent, StepIndex: i})
+	}
+
+	joined := make(chan *roomViewer, 1)
+	go func() {
+		_, viewer := hub.join("long", nil)
+		joined <- viewer
+	}()
+
+	var viewer *roomViewer
+	select {
+	case viewer = <-joined:
+	case <-time.After(5 * time.Second):
+		t.Fatal("join blocked while sending the room history")
+	}
+	if viewer.dropped.Load() {
+		t.Fatal("viewer was dropped while receiving the history")
+	}
+	// metadata, semua step, lalu pesan room
+	if got, want := len(viewer.send), steps+2; got != want {
+		t.Fatalf("expected %d queued messages, got %d", want, got)
+	}
+
+	// room tetap bisa dipakai setelah join
+	room.broadcast(AnimationStep{Type: messageEvent, StepIndex: steps})
+	if room.viewerCount() != 1 {
+		t.Fatalf("expected 1 viewer, got %d", room.viewerCount())
+	}
+}
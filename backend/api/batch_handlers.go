@@ -0,0 +1,171 @@
+package api
+
+import (
+	alg "backend/internal/algorithm"
+	"backend/utils"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	maxBatchConcurrency = 16
+	maxBatchResults     = 50
+)
+
+// batchRequest adalah body untuk POST /api/search/batch
+type batchRequest struct {
+	Targets     []string `json:"targets"`
+	All         bool     `json:"all"`
+	MaxResults  int      `json:"maxResults"`
+	SinglePath  bool     `json:"singlePath"`
+	Concurrency int      `json:"concurrency"`
+}
+
+// HandleBatchSearch mencari resep untuk banyak target sekaligus. Hasil dikirim
+// sebagai NDJSON, satu baris per target begitu target itu selesai, lalu satu
+// baris ringkasan di akhir.
+func (h *Handler) HandleBatchSearch(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed, use POST", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req batchRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, fmt.Sprintf("Invalid request body: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	if req.All {
+		req.Targets = h.names
+	}
+	if len(req.Targets) == 0 {
+		http.Error(w, "No targets given (use targets or all)", http.StatusBadRequest)
+		return
+	}
+	if req.MaxResults <= 0 {
+		req.MaxResults = 1
+	}
+	if req.MaxResults > maxBatchResults {
+		req.MaxResults = maxBatchResults
+	}
+	if req.Concurrency <= 0 {
+		req.Concurrency = alg.DefaultSearchConcurrency
+	}
+	if req.Concurrency > maxBatchConcurrency {
+		req.Concurrency = maxBatchConcurrency
+	}
+
+	log.Printf("DEBUG: Batch search for %d targets (maxResults %d, concurrency %d)", len(req.Targets), req.MaxResults, req.Concurrency)
+
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	flusher, _ := w.(http.Flusher)
+	encoder := json.NewEncoder(w)
+
+	var writeMutex sync.Mutex
+	succeeded, failed := 0, 0
+	writeLine := func(line map[string]interface{}) {
+		writeMutex.Lock()
+		defer writeMutex.Unlock()
+
+		if line["status"] == "ok" {
+			succeeded++
+		} else {
+			failed++
+		}
+		if err := encoder.Encode(line); err != nil {
+			log.Printf("ERROR: Failed to write batch result: %v", err)
+			return
+		}
+		if flusher != nil {
+			flusher.Flush()
+		}
+	}
+
+	// target yang tidak ada di dataset langsung dilaporkan, sisanya dicari
+	searchTargets := make([]string, 0, len(req.Targets))
+	seen := make(map[string]bool, len(req.Targets))
+	for _, target := range req.Targets {
+		if seen[target] {
+			continue
+		}
+		seen[target] = true
+
+		if _, exists := h.elements[target]; !exists {
+			line := map[string]interface{}{
+				"target": target,
+				"status": "error",
+				"error":  "Element not found",
+			}
+			if suggestions := utils.RankNames(target, h.names, 5); len(suggestions) > 0 {
+				names := make([]string, len(suggestions))
+				for i, match := range suggestions {
+					names[i] = match.Name
+				}
+				line["suggestions"] = names
+			}
+			writeLine(line)
+			continue
+		}
+		searchTargets = append(searchTargets, target)
+	}
+
+	startTime := time.Now()
+	alg.ConcurrentElementSearchStream(r.Context(), h.elements, searchTargets, req.MaxResults, req.SinglePath, req.Concurrency, func(result alg.TargetResult) {
+		writeLine(h.batchResultLine(result))
+	})
+
+	writeMutex.Lock()
+	defer writeMutex.Unlock()
+	summary := map[string]interface{}{
+		"done":        true,
+		"total":       succeeded + failed,
+		"succeeded":   succeeded,
+		"failed":      failed,
+		"timeElapsed": time.Since(startTime).Milliseconds(),
+	}
+	if err := encoder.Encode(summary); err != nil {
+		log.Printf("ERROR: Failed to write batch summary: %v", err)
+	}
+}
+
+func (h *Handler) batchResultLine(result alg.TargetResult) map[string]interface{} {
+	line := map[string]interface{}{
+		"target":       result.Target,
+		"nodesVisited": result.Visited,
+		"timeElapsed":  result.Elapsed.Milliseconds(),
+	}
+
+	if result.Err != nil {
+		line["status"] = "error"
+		line["error"] = result.Err.Error()
+		return line
+	}
+
+	baseElements := []string{"Water", "Fire", "Earth", "Air"}
+	if utils.IsBaseElementName(result.Target, baseElements) {
+		line["status"] = "ok"
+		line["isBaseElement"] = true
+		line["trees"] = []interface{}{}
+		return line
+	}
+
+	trees := h.pathsToTrees(result.Paths, result.Target, 0)
+
+	if len(trees) == 0 {
+		line["status"] = "error"
+		line["error"] = "No recipe found"
+		if explanation := alg.ExplainElement(h.graph, result.Target, baseElements, h.reachable, h.report); explanation.Reason != "" {
+			line["reason"] = explanation.Reason
+		}
+		return line
+	}
+
+	line["status"] = "ok"
+	line["trees"] = trees
+	return line
+}
@@ -0,0 +1,157 @@
+package api
+
+import (
+	"backend/model"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// catalogueQuery adalah filter, urutan, dan paging untuk daftar elemen
+type catalogueQuery struct {
+	minTier    int
+	maxTier    int
+	prefix     string
+	contains   string
+	hasRecipes *bool
+	sortBy     string
+	descending bool
+	limit      int
+	offset     int
+}
+
+func parseCatalogueQuery(values url.Values) (catalogueQuery, error) {
+	query := catalogueQuery{
+		minTier: -1,
+		maxTier: -1,
+		prefix:  strings.ToLower(values.Get("prefix")),
+		sortBy:  "name",
+	}
+
+	query.contains = strings.ToLower(values.Get("contains"))
+	if query.contains == "" {
+		query.contains = strings.ToLower(values.Get("q"))
+	}
+
+	intParams := []struct {
+		name   string
+		target *int
+	}{
+		{"minTier", &query.minTier},
+		{"maxTier", &query.maxTier},
+		{"limit", &query.limit},
+		{"offset", &query.offset},
+	}
+	for _, param := range intParams {
+		raw := values.Get(param.name)
+		if raw == "" {
+			continue
+		}
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed < 0 {
+			return query, fmt.Errorf("invalid %s: %q", param.name, raw)
+		}
+		*param.target = parsed
+	}
+
+	if raw := values.Get("hasRecipes"); raw != "" {
+		parsed, err := strconv.ParseBool(raw)
+		if err != nil {
+			return query, fmt.Errorf("invalid hasRecipes: %q", raw)
+		}
+		query.hasRecipes = &parsed
+	}
+
+	if raw := values.Get("sort"); raw != "" {
+		switch raw {
+		case "name", "tier", "recipes", "uses":
+			query.sortBy = raw
+		default:
+			return query, fmt.Errorf("invalid sort: %q (use name, tier, recipes or uses)", raw)
+		}
+	}
+
+	switch values.Get("order") {
+	case "", "asc":
+	case "desc":
+		query.descending = true
+	default:
+		return query, fmt.Errorf("invalid order: %q (use asc or desc)", values.Get("order"))
+	}
+
+	return query, nil
+}
+
+func (q catalogueQuery) matches(element model.Element) bool {
+	if q.minTier >= 0 && element.Tier < q.minTier {
+		return false
+	}
+	if q.maxTier >= 0 && element.Tier > q.maxTier {
+		return false
+	}
+
+	name := strings.ToLower(element.Name)
+	if q.prefix != "" && !strings.HasPrefix(name, q.prefix) {
+		return false
+	}
+	if q.contains != "" && !strings.Contains(name, q.contains) {
+		return false
+	}
+
+	if q.hasRecipes != nil && (len(element.Recipes) > 0) != *q.hasRecipes {
+		return false
+	}
+
+	return true
+}
+
+// queryCatalogue mengembalikan satu halaman elemen yang cocok dengan query
+// beserta jumlah total elemen yang cocok sebelum paging.
+func (h *Handler) queryCatalogue(q catalogueQuery) ([]model.Element, int) {
+	matched := make([]model.Element, 0, len(h.elements))
+	for _, element := range h.elements {
+		if q.matches(element) {
+			matched = append(matched, element)
+		}
+	}
+
+	sortKey := func(element model.Element) int {
+		switch q.sortBy {
+		case "tier":
+			return element.Tier
+		case "recipes":
+			return len(element.Recipes)
+		case "uses":
+			return h.uses[element.Name]
+		}
+		return 0
+	}
+
+	sort.Slice(matched, func(i, j int) bool {
+		ki, kj := sortKey(matched[i]), sortKey(matched[j])
+		if ki != kj {
+			if q.descending {
+				return ki > kj
+			}
+			return ki < kj
+		}
+		if q.sortBy == "name" && q.descending {
+			return matched[i].Name > matched[j].Name
+		}
+		return matched[i].Name < matched[j].Name
+	})
+
+	total := len(matched)
+	if q.offset >= total {
+		return []model.Element{}, total
+	}
+
+	page := matched[q.offset:]
+	if q.limit > 0 && len(page) > q.limit {
+		page = page[:q.limit]
+	}
+
+	return page, total
+}
@@ -0,0 +1,170 @@
+package api
+
+import (
+	alg "backend/internal/algorithm"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const maxCompareCount = 20
+
+// algorithmRun adalah hasil satu algoritma di perbandingan
+type algorithmRun struct {
+	Algorithm    string                   `json:"algorithm"`
+	Description  string                   `json:"description"`
+	NodesVisited int                      `json:"nodesVisited"`
+	TimeElapsed  float64                  `json:"timeElapsed"`
+	PathsFound   int                      `json:"pathsFound"`
+	TreesFound   int                      `json:"treesFound"`
+	MaxDepth     int                      `json:"maxDepth"`
+	MinTreeSize  int                      `json:"minTreeSize"`
+	MaxTreeSize  int                      `json:"maxTreeSize"`
+	AvgTreeSize  float64                  `json:"avgTreeSize"`
+	Error        string                   `json:"error,omitempty"`
+	Trees        []map[string]interface{} `json:"trees,omitempty"`
+}
+
+// HandleCompare menjalankan semua algoritma terdaftar untuk satu elemen secara
+// berurutan dengan parameter yang sama, supaya waktu dan jumlah node bisa
+// dibandingkan langsung.
+func (h *Handler) HandleCompare(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	elementName := strings.TrimSpace(strings.TrimPrefix(r.URL.Path, "/api/compare/"))
+
+	if _, exists := h.elements[elementName]; !exists {
+		h.writeElementNotFound(w, elementName)
+		return
+	}
+
+	count := 3
+	if countParam := r.URL.Query().Get("count"); countParam != "" {
+		if parsedCount, err := strconv.Atoi(countParam); err == nil && parsedCount > 0 {
+			count = min(parsedCount, maxCompareCount)
+		}
+	}
+	singlePath := r.URL.Query().Get("single") == "true"
+	includeTrees := r.URL.Query().Get("trees") == "true"
+
+	algorithms := alg.Algorithms()
+	if algoParam := r.URL.Query().Get("algorithms"); algoParam != "" {
+		algorithms = algorithms[:0:0]
+		for _, name := range strings.Split(algoParam, ",") {
+			algorithm, exists := alg.LookupAlgorithm(strings.TrimSpace(name))
+			if !exists {
+				http.Error(w, fmt.Sprintf("Unknown algorithm: %q", name), http.StatusBadRequest)
+				return
+			}
+			algorithms = append(algorithms, algorithm)
+		}
+	}
+
+	log.Printf("DEBUG: Comparing %d algorithms for element '%s' (count %d)", len(algorithms), elementName, count)
+
+	runs := make([]algorithmRun, 0, len(algorithms))
+	fastest, fewestVisited := "", ""
+	for _, algorithm := range algorithms {
+		run := h.runAlgorithm(algorithm, elementName, count, singlePath)
+		if !includeTrees {
+			run.Trees = nil
+		}
+
+		if run.Error == "" && run.TreesFound > 0 {
+			if fastest == "" || run.TimeElapsed < findRun(runs, fastest).TimeElapsed {
+				fastest = run.Algorithm
+			}
+			if fewestVisited == "" || run.NodesVisited < findRun(runs, fewestVisited).NodesVisited {
+				fewestVisited = run.Algorithm
+			}
+		}
+		runs = append(runs, run)
+	}
+
+	result := map[string]interface{}{
+		"element":       elementName,
+		"count":         count,
+		"singlePath":    singlePath,
+		"results":       runs,
+		"fastest":       fastest,
+		"fewestVisited": fewestVisited,
+	}
+	if err := json.NewEncoder(w).Encode(result); err != nil {
+		http.Error(w, "Failed to encode comparison", http.StatusInternalServerError)
+		log.Printf("Error encoding comparison: %v", err)
+	}
+}
+
+func (h *Handler) runAlgorithm(algorithm alg.RegisteredAlgorithm, elementName string, count int, singlePath bool) (run algorithmRun) {
+	run = algorithmRun{Algorithm: algorithm.Name, Description: algorithm.Description}
+
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("ERROR: %s panicked on %s: %v", algorithm.Name, elementName, r)
+			run.Error = fmt.Sprintf("search failed: %v", r)
+		}
+	}()
+
+	// GC dulu biar sampah dari algoritma sebelumnya tidak ikut kehitung
+	runtime.GC()
+	startTime := time.Now()
+	paths, visited := algorithm.Search(h.elements, elementName, count, singlePath)
+	run.TimeElapsed = float64(time.Since(startTime).Microseconds()) / 1000
+	run.NodesVisited = visited
+	run.PathsFound = len(paths)
+
+	run.Trees = h.pathsToTrees(paths, elementName, 0)
+	totalSize := 0
+	for _, tree := range run.Trees {
+		size := countNodesInTree(tree)
+		totalSize += size
+		if run.MinTreeSize == 0 || size < run.MinTreeSize {
+			run.MinTreeSize = size
+		}
+		if size > run.MaxTreeSize {
+			run.MaxTreeSize = size
+		}
+		if depth := treeDepth(tree); depth > run.MaxDepth {
+			run.MaxDepth = depth
+		}
+	}
+
+	run.TreesFound = len(run.Trees)
+	if run.TreesFound > 0 {
+		run.AvgTreeSize = float64(totalSize) / float64(run.TreesFound)
+	}
+
+	return run
+}
+
+func findRun(runs []algorithmRun, name string) algorithmRun {
+	for _, run := range runs {
+		if run.Algorithm == name {
+			return run
+		}
+	}
+	return algorithmRun{}
+}
+
+// treeDepth menghitung kedalaman pohon resep, elemen dasar di kedalaman 0
+func treeDepth(tree map[string]interface{}) int {
+	ingredients, ok := tree["ingredients"].([]interface{})
+	if !ok || len(ingredients) == 0 {
+		return 0
+	}
+
+	deepest := 0
+	for _, ing := range ingredients {
+		if ingTree, ok := ing.(map[string]interface{}); ok {
+			if depth := treeDepth(ingTree); depth > deepest {
+				deepest = depth
+			}
+		}
+	}
+
+	return deepest + 1
+}
@@ -1,21 +1,35 @@
 package api
 
 import (
-	"backend/model"
+	alg "backend/internal/algorithm"
+	"backend/utils"
 	"encoding/json"
 	"log"
 	"net/http"
+	"strconv"
 	"strings"
 )
 
 func (h *Handler) HandleGetElements(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 	path := strings.TrimPrefix(r.URL.Path, "/api/elements/")
+	if path == "search" {
+		h.HandleSearchElements(w, r)
+		return
+	}
+	if strings.HasSuffix(path, "/explain") {
+		h.HandleExplainElement(w, r)
+		return
+	}
+	if strings.HasSuffix(path, "/prerequisites") {
+		h.HandlePrerequisites(w, r)
+		return
+	}
 	if path != "" && path != "elements" {
 		elementName := strings.TrimSpace(path)
 		element, exists := h.elements[elementName]
 		if !exists {
-			http.Error(w, "Element not found", http.StatusNotFound)
+			h.writeElementNotFound(w, elementName)
 			return
 		}
 		if err := json.NewEncoder(w).Encode(element); err != nil {
@@ -24,13 +38,87 @@ func (h *Handler) HandleGetElements(w http.ResponseWriter, r *http.Request) {
 		}
 		return
 	}
-	elementList := make([]model.Element, 0, len(h.elements))
-	for _, elem := range h.elements {
-		elementList = append(elementList, elem)
+	query, err := parseCatalogueQuery(r.URL.Query())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
 	}
+
+	elementList, total := h.queryCatalogue(query)
+	w.Header().Set("X-Total-Count", strconv.Itoa(total))
 	if err := json.NewEncoder(w).Encode(elementList); err != nil {
 		http.Error(w, "Failed to encode elements", http.StatusInternalServerError)
 		log.Printf("Error encoding elements: %v", err)
 		return
 	}
 }
+
+func (h *Handler) HandleExplainElement(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	path := strings.TrimPrefix(r.URL.Path, "/api/elements/")
+	elementName := strings.TrimSpace(strings.TrimSuffix(path, "/explain"))
+
+	if _, exists := h.elements[elementName]; !exists {
+		h.writeElementNotFound(w, elementName)
+		return
+	}
+
+	explanation := alg.ExplainElement(h.graph, elementName, h.graph.BaseElements, h.reachable, h.report)
+	if err := json.NewEncoder(w).Encode(explanation); err != nil {
+		http.Error(w, "Failed to encode explanation", http.StatusInternalServerError)
+		log.Printf("Error encoding explanation: %v", err)
+	}
+}
+
+func (h *Handler) HandleSearchElements(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	query := r.URL.Query().Get("q")
+	if strings.TrimSpace(query) == "" {
+		http.Error(w, "Missing query parameter q", http.StatusBadRequest)
+		return
+	}
+
+	limit := 10
+	if limitParam := r.URL.Query().Get("limit"); limitParam != "" {
+		if parsedLimit, err := strconv.Atoi(limitParam); err == nil && parsedLimit > 0 {
+			limit = min(parsedLimit, 100)
+		}
+	}
+
+	matches := utils.RankNames(query, h.names, limit)
+	results := make([]map[string]interface{}, 0, len(matches))
+	for _, match := range matches {
+		element := h.elements[match.Name]
+		results = append(results, map[string]interface{}{
+			"name":      match.Name,
+			"score":     match.Score,
+			"distance":  match.Distance,
+			"tier":      element.Tier,
+			"imagePath": element.ImagePath,
+		})
+	}
+
+	if err := json.NewEncoder(w).Encode(results); err != nil {
+		http.Error(w, "Failed to encode search results", http.StatusInternalServerError)
+		log.Printf("Error encoding search results: %v", err)
+	}
+}
+
+// writeElementNotFound mengirim 404 beserta saran nama elemen yang mirip
+func (h *Handler) writeElementNotFound(w http.ResponseWriter, elementName string) {
+	suggestions := make([]string, 0, 5)
+	for _, match := range utils.RankNames(elementName, h.names, 5) {
+		suggestions = append(suggestions, match.Name)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusNotFound)
+	if err := json.NewEncoder(w).Encode(map[string]interface{}{
+		"error":       "Element not found",
+		"element":     elementName,
+		"suggestions": suggestions,
+	}); err != nil {
+		log.Printf("Error encoding not found response: %v", err)
+	}
+}
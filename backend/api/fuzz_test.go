@@ -0,0 +1,246 @@
+package api
+
+import (
+	"backend/internal"
+	"backend/internal/recordings"
+	"backend/internal/synthetic"
+	"backend/model"
+	"backend/utils"
+	"bytes"
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// batas waktu satu request di fuzz test. Dataset fuzz kecil, jadi request
+// yang lebih lama dari ini hampir pasti pencarian yang tidak dibatasi.
+const fuzzRequestTimeout = 10 * time.Second
+
+// nama elemen tambahan di dataset fuzz yang susah ditaruh di URL
+var awkwardNames = []string{"Salt/Pepper", "Ice Cream", "Éclair", "100%", "Yin+Yang", "日本"}
+
+// route yang menerima nama elemen (atau id) di path atau query, sama seperti
+// main.go. {name} dan {count} diganti input fuzz.
+var fuzzRoutes = []struct {
+	path  string
+	query string
+	// false untuk route yang tidak membaca nama elemen dari path
+	element bool
+}{
+	{"/api/elements/{name}", "", true},
+	{"/api/elements/{name}/explain", "", true},
+	{"/api/elements/{name}/prerequisites", "", true},
+	{"/api/elements/search", "q={name}&limit={count}", false},
+	{"/api/analysis/importance/{name}", "", true},
+	{"/api/complexity/{name}", "", true},
+	{"/api/compare/{name}", "count={count}", true},
+	{"/api/bfs-tree/{name}", "count={count}&multithreaded=false", true},
+	{"/api/bfs-tree/{name}", "count={count}", true},
+	{"/api/dfs-tree/{name}", "count={count}", true},
+	{"/api/bidirectional/{name}", "count={count}&tree=true", true},
+	{"/api/bidirectional/{name}", "count={count}&multithreaded=false", true},
+	{"/api/animation-ws/{name}", "algorithm={name}", true},
+	{"/api/recordings/{name}", "", false},
+	{"/api/jobs/{name}", "", false},
+}
+
+var (
+	fuzzHandlerOnce sync.Once
+	fuzzHandlerMux  *http.ServeMux
+	fuzzElements    map[string]model.Element
+	// folder sementara dataset dan rekaman fuzz, dihapus TestMain
+	fuzzDir string
+)
+
+func TestMain(m *testing.M) {
+	code := m.Run()
+	if fuzzDir != "" {
+		os.RemoveAll(fuzzDir)
+	}
+	os.Exit(code)
+}
+
+// newFuzzMux memuat dataset fuzz sekali: graf sintetis kecil ditambah
+// awkwardNames, supaya setiap input fuzz cepat selesai
+func newFuzzMux(tb testing.TB) (*http.ServeMux, map[string]model.Element) {
+	tb.Helper()
+	log.SetOutput(io.Discard)
+	tb.Cleanup(func() { log.SetOutput(os.Stderr) })
+
+	fuzzHandlerOnce.Do(func() {
+		generated, err := synthetic.Generate(synthetic.Config{Elements: 30, MinRecipes: 1, MaxRecipes: 3, FanIn: 2, Seed: 1})
+		if err != nil {
+			tb.Fatal(err)
+		}
+		for _, name := range awkwardNames {
+			generated = append(generated, model.Element{
+				Name:    name,
+				Tier:    1,
+				Recipes: []model.ElementRecipe{{Ingredients: []string{"Water", "Fire"}}},
+			})
+		}
+
+		dir, err := os.MkdirTemp("", "fuzz-handlers")
+		if err != nil {
+			tb.Fatal(err)
+		}
+		fuzzDir = dir
+		datasetPath := filepath.Join(dir, "elements.json")
+		var buf bytes.Buffer
+		if err := synthetic.Write(&buf, generated); err != nil {
+			tb.Fatal(err)
+		}
+		if err := os.WriteFile(datasetPath, buf.Bytes(), 0o644); err != nil {
+			tb.Fatal(err)
+		}
+		dataset, err := internal.LoadDataset(datasetPath, utils.DefaultValidationPolicy())
+		if err != nil {
+			tb.Fatal(err)
+		}
+
+		h := NewHandler(dataset)
+		h.recordings = recordings.NewStore(filepath.Join(dir, "recordings"))
+
+		mux := http.NewServeMux()
+		mux.HandleFunc("/api/elements/", h.HandleGetElements)
+		mux.HandleFunc("/api/analysis/importance", h.HandleImportance)
+		mux.HandleFunc("/api/analysis/importance/", h.HandleImportance)
+		mux.HandleFunc("/api/complexity", h.HandleComplexity)
+		mux.HandleFunc("/api/complexity/", h.HandleComplexity)
+		mux.HandleFunc("/api/search/batch", h.HandleBatchSearch)
+		mux.HandleFunc("/api/compare/", h.HandleCompare)
+		mux.HandleFunc("/api/jobs", h.HandleJobs)
+		mux.HandleFunc("/api/jobs/", h.HandleJobs)
+		mux.HandleFunc("/api/recordings", h.HandleRecordings)
+		mux.HandleFunc("/api/recordings/", h.HandleRecordings)
+		mux.HandleFunc("/api/bfs-tree/", h.HandleBFSTree)
+		mux.HandleFunc("/api/dfs-tree/", h.HandleDFSTree)
+		mux.HandleFunc("/api/bidirectional/", h.HandleBidirectionalSearch)
+		mux.HandleFunc("/api/animation-ws/", h.HandleAnimationWebSocket)
+
+		fuzzHandlerMux, fuzzElements = mux, dataset.Elements
+	})
+	if fuzzHandlerMux == nil {
+		tb.Fatal("fuzz dataset failed to load")
+	}
+	return fuzzHandlerMux, fuzzElements
+}
+
+// serveFuzz menjalankan satu request dan gagal kalo handler panic atau lebih
+// lama dari fuzzRequestTimeout
+func serveFuzz(t *testing.T, mux *http.ServeMux, r *http.Request) *httptest.ResponseRecorder {
+	t.Helper()
+	recorder := httptest.NewRecorder()
+	done := make(chan interface{}, 1)
+	go func() {
+		defer func() { done <- recover() }()
+		mux.ServeHTTP(recorder, r)
+	}()
+
+	select {
+	case recovered := <-done:
+		if recovered != nil {
+			t.Fatalf("%s %s panicked: %v", r.Method, r.URL, recovered)
+		}
+	case <-time.After(fuzzRequestTimeout):
+		t.Fatalf("%s %s took longer than %v", r.Method, r.URL, fuzzRequestTimeout)
+	}
+	return recorder
+}
+
+// checkResponse memastikan status code masuk akal dan body JSON valid
+func checkResponse(t *testing.T, r *http.Request, recorder *httptest.ResponseRecorder, allowed ...int) {
+	t.Helper()
+	status := recorder.Code
+	isAllowed := false
+	for _, code := range allowed {
+		if status == code {
+			isAllowed = true
+		}
+	}
+	if !isAllowed {
+		t.Fatalf("%s %s: unexpected status %d, body %q", r.Method, r.URL, status, recorder.Body.String())
+	}
+
+	contentType := recorder.Header().Get("Content-Type")
+	if strings.HasPrefix(contentType, "application/json") && status != http.StatusTemporaryRedirect {
+		if !json.Valid(recorder.Body.Bytes()) {
+			t.Fatalf("%s %s: status %d with invalid JSON body %q", r.Method, r.URL, status, recorder.Body.String())
+		}
+	}
+}
+
+// FuzzElementRoutes mengirim nama elemen dan count acak ke setiap route yang
+// membaca nama dari path. Nama yang ada di dataset harus selalu ketemu,
+// apapun isinya (spasi, "/", "%", "+", unicode).
+func FuzzElementRoutes(f *testing.F) {
+	names := []string{"Water", "Element 07", "", " ", "/", "a/../b", "a//b", "%", "%2F", "%252F",
+		"Water?count=1", "Water#x", "room/abc", "\x00", "\xff\xfe", strings.Repeat("Fire", 300)}
+	names = append(names, awkwardNames...)
+	counts := []string{"3", "", "0", "-1", "all", "100", "101", "1e3", " 5", "%",
+		"99999999999999999999", "9223372036854775807", "4611686018427387904", "٣"}
+	for i, name := range names {
+		f.Add(name, counts[i%len(counts)])
+	}
+	for _, count := range counts {
+		f.Add("Salt/Pepper", count)
+	}
+
+	f.Fuzz(func(t *testing.T, name, count string) {
+		mux, elements := newFuzzMux(t)
+		_, known := elements[name]
+
+		for _, route := range fuzzRoutes {
+			query := strings.NewReplacer("{name}", url.QueryEscape(name), "{count}", url.QueryEscape(count)).Replace(route.query)
+			target := &url.URL{Path: strings.ReplaceAll(route.path, "{name}", name), RawQuery: query}
+
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			r.URL, r.RequestURI = target, target.RequestURI()
+			recorder := serveFuzz(t, mux, r)
+			checkResponse(t, r, recorder, http.StatusOK, http.StatusTemporaryRedirect, http.StatusBadRequest, http.StatusNotFound)
+
+			// nama yang ada di dataset dan path yang sudah bersih (tidak
+			// di-redirect ServeMux) harus sampai ke elemen yang benar
+			if route.element && known && path.Clean(target.Path) == target.Path && recorder.Code == http.StatusNotFound {
+				t.Fatalf("GET %s: element %q exists but got 404", target, name)
+			}
+		}
+	})
+}
+
+// FuzzPostBodies mengirim body acak ke endpoint yang menerima JSON
+func FuzzPostBodies(f *testing.F) {
+	seeds := []string{
+		`{"element":"Salt/Pepper","count":3}`,
+		`{"element":"Element 07","algorithm":"bfs","count":9223372036854775807}`,
+		`{"element":"Water","count":-9223372036854775808,"singlePath":true}`,
+		`{"element":"100%","algorithm":"nope"}`,
+		`{"targets":["Ice Cream","Éclair","missing"],"maxResults":9223372036854775807,"concurrency":-1}`,
+		`{"all":true,"maxResults":1,"concurrency":1000000}`,
+		`{"targets":[]}`,
+		`{"count":"3"}`,
+		`[]`, `null`, `{`, ``, "\x00",
+	}
+	for _, seed := range seeds {
+		f.Add([]byte(seed))
+	}
+
+	f.Fuzz(func(t *testing.T, body []byte) {
+		mux, _ := newFuzzMux(t)
+		for _, endpoint := range []string{"/api/jobs", "/api/search/batch"} {
+			r := httptest.NewRequest(http.MethodPost, endpoint, bytes.NewReader(body))
+			recorder := serveFuzz(t, mux, r)
+			checkResponse(t, r, recorder, http.StatusOK, http.StatusAccepted, http.StatusBadRequest, http.StatusNotFound, http.StatusServiceUnavailable)
+		}
+	})
+}
@@ -0,0 +1,178 @@
+package api
+
+import (
+	"backend/internal"
+	"backend/utils"
+	"bytes"
+	"encoding/json"
+	"flag"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "tulis ulang file golden di testdata/golden")
+
+// snapshot dataset yang dipakai test, sengaja tidak memakai elements.json
+// supaya update dataset tidak mengubah hasil golden
+const goldenDataset = "../testdata/elements.json"
+
+// goldenCases adalah request yang hasilnya dikunci. Semua algoritma memakai
+// mode single-threaded supaya urutan goroutine tidak mengubah hasil.
+var goldenCases = []struct {
+	name string
+	url  string
+}{
+	{"bfs_brick", "/api/bfs-tree/Brick?count=1&multithreaded=false"},
+	{"bfs_glass", "/api/bfs-tree/Glass?count=1&multithreaded=false"},
+	{"bfs_human", "/api/bfs-tree/Human?count=1&multithreaded=false"},
+	{"bfs_robot", "/api/bfs-tree/Robot?count=1&multithreaded=false"},
+	{"bfs_water", "/api/bfs-tree/Water?count=1&multithreaded=false"},
+	{"bfs_time", "/api/bfs-tree/Time?count=1&multithreaded=false"},
+	{"bfs_not_found", "/api/bfs-tree/Brik?count=1&multithreaded=false"},
+	{"dfs_brick", "/api/dfs-tree/Brick?count=1&multithreaded=false"},
+	{"dfs_glass", "/api/dfs-tree/Glass?count=1&multithreaded=false"},
+	{"dfs_human", "/api/dfs-tree/Human?count=1&multithreaded=false"},
+	{"dfs_robot", "/api/dfs-tree/Robot?count=1&multithreaded=false"},
+	{"dfs_water", "/api/dfs-tree/Water?count=1&multithreaded=false"},
+	{"dfs_time", "/api/dfs-tree/Time?count=1&multithreaded=false"},
+	{"bidirectional_brick", "/api/bidirectional/Brick?count=1&multithreaded=false&tree=true"},
+	{"bidirectional_glass", "/api/bidirectional/Glass?count=1&multithreaded=false&tree=true"},
+	{"bidirectional_human", "/api/bidirectional/Human?count=1&multithreaded=false&tree=true"},
+	{"bidirectional_robot", "/api/bidirectional/Robot?count=1&multithreaded=false&tree=true"},
+	{"bidirectional_water", "/api/bidirectional/Water?count=1&multithreaded=false&tree=true"},
+	{"bidirectional_time", "/api/bidirectional/Time?count=1&multithreaded=false&tree=true"},
+	{"bfs_robot_3", "/api/bfs-tree/Robot?count=3&multithreaded=false"},
+	{"dfs_robot_3", "/api/dfs-tree/Robot?count=3&multithreaded=false"},
+	{"bidirectional_robot_3", "/api/bidirectional/Robot?count=3&multithreaded=false&tree=true"},
+	{"bidirectional_swamp_3", "/api/bidirectional/Swamp?count=3&multithreaded=false&tree=true"},
+}
+
+// field yang berubah setiap run atau tidak mempengaruhi bentuk pohon
+var volatileFields = map[string]bool{
+	"timeElapsed":     true,
+	"nodesVisited":    true,
+	"imagePath":       true,
+	"pathIndex":       true,
+	"ingredientIndex": true,
+}
+
+func newGoldenHandler(t *testing.T) *Handler {
+	t.Helper()
+	log.SetOutput(io.Discard)
+	t.Cleanup(func() { log.SetOutput(os.Stderr) })
+
+	dataset, err := internal.LoadDataset(goldenDataset, utils.DefaultValidationPolicy())
+	if err != nil {
+		t.Fatalf("failed to load %s: %v", goldenDataset, err)
+	}
+	t.Setenv("RECORDINGS_DIR", t.TempDir())
+	return NewHandler(dataset)
+}
+
+func TestSearchGolden(t *testing.T) {
+	h := newGoldenHandler(t)
+	routes := map[string]http.HandlerFunc{
+		"/api/bfs-tree/":      h.HandleBFSTree,
+		"/api/dfs-tree/":      h.HandleDFSTree,
+		"/api/bidirectional/": h.HandleBidirectionalSearch,
+	}
+
+	for _, tc := range goldenCases {
+		t.Run(tc.name, func(t *testing.T) {
+			parsed, err := url.Parse(tc.url)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var handler http.HandlerFunc
+			for prefix, route := range routes {
+				if strings.HasPrefix(parsed.Path, prefix) {
+					handler = route
+				}
+			}
+
+			recorder := httptest.NewRecorder()
+			handler(recorder, httptest.NewRequest(http.MethodGet, tc.url, nil))
+
+			got := normalizeResponse(t, recorder)
+			path := filepath.Join("testdata", "golden", tc.name+".json")
+			if *update {
+				if err := os.WriteFile(path, got, 0o644); err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+
+			want, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatalf("%v (run go test ./api -run TestSearchGolden -update to create it)", err)
+			}
+			if !bytes.Equal(got, want) {
+				t.Errorf("response for %s changed, run with -update if this is intended\n--- want\n%s\n--- got\n%s", tc.url, want, got)
+			}
+		})
+	}
+}
+
+// normalizeResponse membuang field yang berubah-ubah, mengurutkan ingredient
+// dan pohon, lalu meng-encode ulang dengan indentasi supaya diff-nya enak dibaca
+func normalizeResponse(t *testing.T, recorder *httptest.ResponseRecorder) []byte {
+	t.Helper()
+
+	var body interface{}
+	if err := json.Unmarshal(recorder.Body.Bytes(), &body); err != nil {
+		t.Fatalf("response is not JSON (status %d): %v\n%s", recorder.Code, err, recorder.Body.String())
+	}
+
+	normalized := map[string]interface{}{
+		"status": recorder.Code,
+		"body":   normalizeValue(body),
+	}
+	out, err := json.MarshalIndent(normalized, "", "  ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return append(out, '\n')
+}
+
+func normalizeValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		result := make(map[string]interface{}, len(v))
+		for key, field := range v {
+			if volatileFields[key] {
+				continue
+			}
+			result[key] = normalizeValue(field)
+		}
+		return result
+	case []interface{}:
+		items := make([]interface{}, len(v))
+		for i, item := range v {
+			items[i] = normalizeValue(item)
+		}
+		// urutan pohon dan ingredient tidak dianggap bagian dari hasil
+		if len(items) > 0 {
+			if _, isObject := items[0].(map[string]interface{}); isObject {
+				sort.SliceStable(items, func(i, j int) bool {
+					return canonical(items[i]) < canonical(items[j])
+				})
+			}
+		}
+		return items
+	default:
+		return v
+	}
+}
+
+func canonical(value interface{}) string {
+	data, _ := json.Marshal(value)
+	return string(data)
+}
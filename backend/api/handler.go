@@ -1,13 +1,80 @@
 package api
 
 import (
+	"backend/internal"
+	"backend/internal/analysis"
+	"backend/internal/graph"
+	"backend/internal/jobs"
+	"backend/internal/recordings"
 	"backend/model"
+	"backend/utils"
+	"sort"
+	"sync"
 )
 
 type Handler struct {
-	elements map[string]model.Element
+	elements  map[string]model.Element
+	graph     *graph.ElementGraph
+	report    utils.ValidationReport
+	reachable map[string]bool
+	names     []string
+	stats     *analysis.Stats
+	jobs      *jobs.Manager
+	// dipakai untuk mengenali rekaman animasi dari dataset yang sama
+	datasetVersion string
+	recordings     *recordings.Store
+	rooms          *animationHub
+	// mode debug, pohon yang tidak valid dibuang sebelum dikirim
+	verifyTrees bool
+	// jumlah resep yang memakai setiap elemen, dihitung sekali untuk sort katalog
+	uses map[string]int
+
+	importanceOnce sync.Once
+	importance     *analysis.ImportanceReport
+
+	complexityOnce sync.Once
+	complexity     map[string]*analysis.Complexity
+}
+
+func NewHandler(dataset *internal.Dataset) *Handler {
+	names := make([]string, 0, len(dataset.Elements))
+	uses := make(map[string]int, len(dataset.Elements))
+	for name := range dataset.Elements {
+		names = append(names, name)
+		uses[name] = dataset.Graph.CountUses(name)
+	}
+	sort.Strings(names)
+
+	return &Handler{
+		elements:  dataset.Elements,
+		graph:     dataset.Graph,
+		report:    dataset.Report,
+		reachable: dataset.Graph.ReachableFrom(dataset.Graph.BaseElements),
+		names:     names,
+		uses:      uses,
+		stats:     analysis.ComputeStats(dataset.Elements, dataset.Graph),
+		jobs:      jobs.NewManager(dataset.Elements, jobs.DefaultConcurrency, jobs.DefaultRetention),
+
+		datasetVersion: dataset.Version,
+		recordings:     recordings.NewStore(recordings.DirFromEnv()),
+		rooms:          newAnimationHub(),
+		verifyTrees:    verifyTreesFromEnv(),
+	}
+}
+
+// importanceReport menghitung analisis kepentingan elemen sekali per dataset,
+// baru dihitung saat pertama kali dibutuhkan karena agak berat.
+func (h *Handler) importanceReport() *analysis.ImportanceReport {
+	h.importanceOnce.Do(func() {
+		h.importance = analysis.ComputeImportance(h.graph)
+	})
+	return h.importance
 }
 
-func NewHandler(elements map[string]model.Element) *Handler {
-	return &Handler{elements: elements}
+// complexityReport menghitung kompleksitas semua elemen sekali per dataset
+func (h *Handler) complexityReport() map[string]*analysis.Complexity {
+	h.complexityOnce.Do(func() {
+		h.complexity = analysis.ComputeComplexity(h.graph)
+	})
+	return h.complexity
 }
@@ -0,0 +1,163 @@
+package api
+
+import (
+	"backend/internal/jobs"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	defaultJobAlgorithm = "multithreaded-dfs"
+	maxJobCount         = 100
+	// sama seperti handler pohon: cari lebih banyak path dari jumlah pohon yang diminta
+	jobPathsPerTree = 20
+	// count <= 0 artinya semua pohon, dibatasi sama seperti dfs-tree
+	jobAllPaths = 1000
+)
+
+// jobRequest adalah body untuk POST /api/jobs
+type jobRequest struct {
+	Element    string `json:"element"`
+	Algorithm  string `json:"algorithm"`
+	Count      int    `json:"count"`
+	SinglePath bool   `json:"singlePath"`
+}
+
+// HandleJobs melayani /api/jobs (POST submit, GET daftar job) dan
+// /api/jobs/{id} (GET status dan hasil, DELETE untuk membatalkan).
+func (h *Handler) HandleJobs(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	jobID := strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/jobs"), "/")
+
+	switch {
+	case jobID == "" && r.Method == http.MethodPost:
+		h.submitJob(w, r)
+	case jobID == "" && r.Method == http.MethodGet:
+		snapshots := h.jobs.List()
+		list := make([]map[string]interface{}, 0, len(snapshots))
+		for _, snapshot := range snapshots {
+			list = append(list, h.jobResponse(snapshot, false))
+		}
+		h.writeJobJSON(w, http.StatusOK, list)
+	case jobID != "" && r.Method == http.MethodGet:
+		snapshot, exists := h.jobs.Get(jobID, true)
+		if !exists {
+			http.Error(w, "Job not found", http.StatusNotFound)
+			return
+		}
+		h.writeJobJSON(w, http.StatusOK, h.jobResponse(snapshot, true))
+	case jobID != "" && r.Method == http.MethodDelete:
+		snapshot, exists := h.jobs.Cancel(jobID)
+		if !exists {
+			http.Error(w, "Job not found", http.StatusNotFound)
+			return
+		}
+		h.writeJobJSON(w, http.StatusOK, h.jobResponse(snapshot, false))
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *Handler) submitJob(w http.ResponseWriter, r *http.Request) {
+	var req jobRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, fmt.Sprintf("Invalid request body: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	req.Element = strings.TrimSpace(req.Element)
+	if _, exists := h.elements[req.Element]; !exists {
+		h.writeElementNotFound(w, req.Element)
+		return
+	}
+	if req.Algorithm == "" {
+		req.Algorithm = defaultJobAlgorithm
+	}
+	if req.Count > maxJobCount {
+		req.Count = maxJobCount
+	}
+
+	maxResults := jobAllPaths
+	if req.Count > 0 {
+		maxResults = req.Count * jobPathsPerTree
+	}
+
+	snapshot, err := h.jobs.Submit(jobs.Request{
+		Element:    req.Element,
+		Algorithm:  req.Algorithm,
+		MaxResults: maxResults,
+		SinglePath: req.SinglePath,
+		Count:      req.Count,
+	})
+	switch {
+	case errors.Is(err, jobs.ErrUnknownAlgorithm):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	case errors.Is(err, jobs.ErrQueueFull):
+		http.Error(w, "Too many pending jobs, try again later", http.StatusServiceUnavailable)
+		return
+	case err != nil:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Location", "/api/jobs/"+snapshot.ID)
+	h.writeJobJSON(w, http.StatusAccepted, h.jobResponse(snapshot, false))
+}
+
+func (h *Handler) jobResponse(snapshot jobs.Snapshot, withTrees bool) map[string]interface{} {
+	response := map[string]interface{}{
+		"id":         snapshot.ID,
+		"status":     snapshot.Status,
+		"element":    snapshot.Request.Element,
+		"algorithm":  snapshot.Request.Algorithm,
+		"count":      snapshot.Request.Count,
+		"singlePath": snapshot.Request.SinglePath,
+		"progress": map[string]interface{}{
+			"nodesVisited": snapshot.Visited,
+			"pathsFound":   snapshot.PathsFound,
+		},
+		"createdAt": snapshot.CreatedAt,
+	}
+
+	if !snapshot.StartedAt.IsZero() {
+		response["startedAt"] = snapshot.StartedAt
+		end := snapshot.FinishedAt
+		if end.IsZero() {
+			end = time.Now()
+		}
+		response["timeElapsed"] = end.Sub(snapshot.StartedAt).Milliseconds()
+	}
+	if !snapshot.FinishedAt.IsZero() {
+		response["finishedAt"] = snapshot.FinishedAt
+	}
+	if snapshot.Error != "" {
+		response["error"] = snapshot.Error
+	}
+
+	if withTrees {
+		trees := h.pathsToTrees(snapshot.Paths, snapshot.Request.Element, snapshot.Request.Count)
+		response["trees"] = trees
+		total := 0
+		for _, tree := range trees {
+			total += countNodesInTree(tree)
+		}
+		response["totalTreeNodes"] = total
+		// pohon dari job yang belum selesai masih sementara
+		response["partial"] = snapshot.Status != jobs.StatusCompleted
+	}
+
+	return response
+}
+
+func (h *Handler) writeJobJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		log.Printf("Error encoding job response: %v", err)
+	}
+}
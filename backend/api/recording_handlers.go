@@ -0,0 +1,115 @@
+package api
+
+import (
+	alg "backend/internal/algorithm"
+	"backend/internal/recordings"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+)
+
+// HandleRecordings melayani GET /api/recordings (daftar rekaman animasi, bisa
+// difilter dengan ?element= dan ?algorithm=) dan GET /api/recordings/{id}
+// (download file rekaman JSON lines).
+func (h *Handler) HandleRecordings(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	id := strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/recordings"), "/")
+	if id != "" {
+		h.downloadRecording(w, r, id)
+		return
+	}
+
+	infos, err := h.recordings.List()
+	if err != nil {
+		log.Printf("ERROR: Failed to list recordings: %v", err)
+		http.Error(w, "Failed to list recordings", http.StatusInternalServerError)
+		return
+	}
+
+	elementFilter := r.URL.Query().Get("element")
+	algorithmFilter := r.URL.Query().Get("algorithm")
+	list := make([]map[string]interface{}, 0, len(infos))
+	for _, info := range infos {
+		if elementFilter != "" && !strings.EqualFold(info.Element, elementFilter) {
+			continue
+		}
+		if algorithmFilter != "" && info.Algorithm != algorithmFilter {
+			continue
+		}
+
+		list = append(list, map[string]interface{}{
+			"id":             info.ID,
+			"element":        info.Element,
+			"algorithm":      info.Algorithm,
+			"datasetVersion": info.DatasetVersion,
+			// rekaman dari dataset lain tetap bisa diputar, tapi hasilnya
+			// mungkin beda dengan pencarian sekarang
+			"current":      info.DatasetVersion == h.datasetVersion,
+			"createdAt":    info.CreatedAt,
+			"steps":        info.Steps,
+			"truncated":    info.Truncated,
+			"nodesVisited": info.NodesVisited,
+			"timeElapsed":  info.TimeElapsed,
+			"size":         info.Size,
+		})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(map[string]interface{}{
+		"datasetVersion": h.datasetVersion,
+		"recordings":     list,
+	}); err != nil {
+		log.Printf("Error encoding recordings: %v", err)
+	}
+}
+
+func (h *Handler) downloadRecording(w http.ResponseWriter, r *http.Request, id string) {
+	path, err := h.recordings.Path(id)
+	if errors.Is(err, recordings.ErrNotFound) || errors.Is(err, recordings.ErrInvalidID) {
+		http.Error(w, "Recording not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		log.Printf("ERROR: Failed to open recording %s: %v", id, err)
+		http.Error(w, "Failed to open recording", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	w.Header().Set("Content-Disposition", `attachment; filename="`+id+`.jsonl"`)
+	http.ServeFile(w, r, path)
+}
+
+// saveRecording menyimpan event dan hasil satu pencarian animasi yang sudah
+// selesai, mengembalikan id rekamannya
+func (h *Handler) saveRecording(element, algorithm string, events *eventLog, result animationResult) (string, error) {
+	events.mu.Lock()
+	rec := &recordings.Recording{
+		Header: recordings.Header{
+			Element:        element,
+			Algorithm:      algorithm,
+			DatasetVersion: h.datasetVersion,
+			Truncated:      events.truncated,
+			NodesVisited:   result.visited,
+			TimeElapsed:    result.elapsed.Milliseconds(),
+		},
+		Events: append([]alg.SearchEvent(nil), events.events...),
+		Paths:  result.paths,
+	}
+	events.mu.Unlock()
+
+	id, err := h.recordings.Save(rec)
+	if err != nil {
+		log.Printf("ERROR: Failed to save recording for %s (%s): %v", element, algorithm, err)
+		return "", err
+	}
+
+	log.Printf("DEBUG: Saved recording %s with %d steps", id, len(rec.Events))
+	return id, nil
+}
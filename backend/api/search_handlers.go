@@ -6,7 +6,6 @@ import (
 	"backend/model"
 	"backend/utils"
 	"encoding/json"
-	"fmt"
 	"log"
 	"net/http"
 	"sort"
@@ -15,21 +14,26 @@ import (
 	"time"
 )
 
+// batas count di handler pohon. Pencarian memakai count*2 sampai count*20
+// path dan ukuran buffer channel ikut count, jadi count yang sangat besar
+// bisa overflow atau bikin panic saat alokasi.
+const maxTreeCount = 100
+
 func (h *Handler) HandleBFSTree(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
-	pathParts := strings.Split(strings.TrimPrefix(r.URL.Path, "/api/bfs-tree/"), "/")
-	if len(pathParts) < 1 {
+	// nama elemen boleh mengandung "/", jadi seluruh sisa path dipakai
+	elementName, ok := strings.CutPrefix(r.URL.Path, "/api/bfs-tree/")
+	if !ok {
 		http.Error(w, "Invalid URL format. Use /api/bfs-tree/{elementName}?count=N", http.StatusBadRequest)
 		return
 	}
-	elementName := strings.Join(pathParts, "/")
 
 	log.Printf("DEBUG: BFS Tree request for element: %s", elementName)
 
 	count := 3
 	if countParam := r.URL.Query().Get("count"); countParam != "" {
 		if parsedCount, err := strconv.Atoi(countParam); err == nil && parsedCount > 0 {
-			count = parsedCount
+			count = min(parsedCount, maxTreeCount)
 			log.Printf("DEBUG: Requested tree count: %d", count)
 		}
 	}
@@ -51,11 +55,21 @@ func (h *Handler) HandleBFSTree(w http.ResponseWriter, r *http.Request) {
 	// Validate element exists
 	_, exists := h.elements[elementName]
 	if !exists {
-		http.Error(w, "Element not found", http.StatusNotFound)
+		h.writeElementNotFound(w, elementName)
 		log.Printf("DEBUG: Element '%s' not found in database", elementName)
 		return
 	}
 
+	if format := streamFormat(r); format != "" {
+		h.streamSearch(w, r, format, elementName, algoName, count, func(monitor *alg.Monitor) ([][]model.Node, int) {
+			if useMultithreaded {
+				return alg.MultiThreadedBFSWithMonitor(h.elements, elementName, count*2, false, monitor)
+			}
+			return alg.BFSWithMonitor(h.elements, elementName, count*2, false, monitor)
+		})
+		return
+	}
+
 	// Handle base elements quickly
 	baseElements := []string{"Water", "Fire", "Earth", "Air"}
 	isBaseElement := false
@@ -121,12 +135,12 @@ func (h *Handler) HandleBFSTree(w http.ResponseWriter, r *http.Request) {
 	}
 
 	for _, path := range pathsToProcess {
-		tree := convertPathToTree(path, elementName, h.elements, baseElements)
+		tree := utils.ConvertPathToTree(path, elementName, h.elements, baseElements)
 
-		ensureIngredientsExpanded(tree, h.elements, baseElements, make(map[string]bool))
+		utils.EnsureIngredientsExpanded(tree, h.elements, baseElements, make(map[string]bool))
 
 		if tree != nil {
-			signature := generateDetailedTreeSignature(tree)
+			signature := utils.DetailedTreeSignature(tree)
 
 			if !uniqueSignatures[signature] {
 				uniqueSignatures[signature] = true
@@ -140,7 +154,7 @@ func (h *Handler) HandleBFSTree(w http.ResponseWriter, r *http.Request) {
 	// Add the code here to filter for makeable trees
 	makeableTrees := make([]map[string]interface{}, 0)
 	for _, tree := range trees {
-		if isTreeFullyMakeable(tree) {
+		if utils.IsTreeFullyMakeable(tree) {
 			makeableTrees = append(makeableTrees, tree)
 		}
 	}
@@ -158,10 +172,10 @@ func (h *Handler) HandleBFSTree(w http.ResponseWriter, r *http.Request) {
 
 		if len(singlePath) > 0 {
 			log.Printf("DEBUG: Got a single path with single path mode, converting to tree")
-			tree := convertPathToTree(singlePath[0], elementName, h.elements, baseElements)
+			tree := utils.ConvertPathToTree(singlePath[0], elementName, h.elements, baseElements)
 			if tree != nil {
-				ensureIngredientsExpanded(tree, h.elements, baseElements, make(map[string]bool))
-				if isTreeFullyMakeable(tree) {
+				utils.EnsureIngredientsExpanded(tree, h.elements, baseElements, make(map[string]bool))
+				if utils.IsTreeFullyMakeable(tree) {
 					makeableTrees = append(makeableTrees, tree)
 					log.Printf("DEBUG: Successfully found a makeable tree with single path mode")
 				}
@@ -228,8 +242,8 @@ func (h *Handler) HandleBFSTree(w http.ResponseWriter, r *http.Request) {
 						tree["ingredients"] = append(tree["ingredients"].([]interface{}), ingTree)
 					}
 
-					ensureIngredientsExpanded(tree, h.elements, baseElements, make(map[string]bool))
-					if isTreeFullyMakeable(tree) {
+					utils.EnsureIngredientsExpanded(tree, h.elements, baseElements, make(map[string]bool))
+					if utils.IsTreeFullyMakeable(tree) {
 						makeableTrees = append(makeableTrees, tree)
 						log.Printf("DEBUG: Found makeable tree from direct recipe")
 						break
@@ -246,6 +260,7 @@ func (h *Handler) HandleBFSTree(w http.ResponseWriter, r *http.Request) {
 				"unmakeable":  false, // Don't mark the top element as unmakeable
 				"ingredients": []interface{}{},
 				"notice":      "This element cannot be fully traced to base elements",
+				"explanation": alg.ExplainElement(h.graph, elementName, baseElements, h.reachable, h.report),
 			}}
 		} else {
 			trees = makeableTrees
@@ -255,6 +270,7 @@ func (h *Handler) HandleBFSTree(w http.ResponseWriter, r *http.Request) {
 		log.Printf("DEBUG: Using %d makeable trees", len(trees))
 	}
 
+	trees = h.rejectInvalidTrees(algoName+" tree", elementName, trees)
 	if len(trees) > count {
 		trees = trees[:count]
 	}
@@ -285,6 +301,17 @@ func (h *Handler) HandleBFSTree(w http.ResponseWriter, r *http.Request) {
 		algoName, len(trees), timeElapsed)
 }
 
+// pathsToTrees mengubah path hasil pencarian jadi pohon resep yang unik,
+// paling banyak limit pohon (limit <= 0 berarti semua)
+func (h *Handler) pathsToTrees(paths [][]model.Node, elementName string, limit int) []map[string]interface{} {
+	return h.rejectInvalidTrees("pathsToTrees", elementName, BuildTrees(h.elements, paths, elementName, limit))
+}
+
+// BuildTrees sama dengan pathsToTrees tapi tanpa Handler, lihat utils.BuildTrees
+func BuildTrees(elements map[string]model.Element, paths [][]model.Node, elementName string, limit int) []map[string]interface{} {
+	return utils.BuildTrees(elements, paths, elementName, limit)
+}
+
 func countNodesInTree(tree map[string]interface{}) int {
 	if tree == nil {
 		return 0
@@ -307,329 +334,57 @@ func countNodesInTree(tree map[string]interface{}) int {
 	return count
 }
 
-func ensureIngredientsExpanded(tree map[string]interface{}, elements map[string]model.Element, baseElements []string, visited map[string]bool) bool {
-	if tree == nil {
-		return false
-	}
-
-	elementName, ok := tree["name"].(string)
-	if !ok || visited[elementName] {
-		return false
-	}
-
-	visited[elementName] = true
-	defer delete(visited, elementName)
-
-	isBase := false
-	for _, base := range baseElements {
-		if elementName == base {
-			isBase = true
-			break
-		}
-	}
-
-	if isBase {
-		tree["isBaseElement"] = true
-		return true
-	}
-
-	// Check if this element is makeable
-	elemData, exists := elements[elementName]
-	if !exists || len(elemData.Recipes) == 0 {
-		tree["unmakeable"] = true
-		return false
-	}
-
-	ingredients, ok := tree["ingredients"].([]interface{})
-	allIngredientsValid := true
-
-	if !ok || len(ingredients) == 0 {
-		recipe := elemData.Recipes[0]
-		newIngredients := make([]interface{}, 0, len(recipe.Ingredients))
-
-		for _, ingName := range recipe.Ingredients {
-			ingIsBase := false
-			for _, base := range baseElements {
-				if ingName == base {
-					ingIsBase = true
-					break
-				}
-			}
-
-			ingData, ingExists := elements[ingName]
-			if !ingExists {
-				allIngredientsValid = false
-				continue
-			}
-
-			ingTree := map[string]interface{}{
-				"name":          ingName,
-				"imagePath":     ingData.ImagePath,
-				"isBaseElement": ingIsBase,
-				"ingredients":   []interface{}{},
-			}
-
-			if !ingIsBase {
-				ingValid := ensureIngredientsExpanded(ingTree, elements, baseElements, visited)
-				if !ingValid {
-					allIngredientsValid = false
-				}
-			}
-
-			newIngredients = append(newIngredients, ingTree)
-		}
-
-		tree["ingredients"] = newIngredients
-	} else {
-		for _, ing := range ingredients {
-			if ingTree, ok := ing.(map[string]interface{}); ok {
-				ingValid := ensureIngredientsExpanded(ingTree, elements, baseElements, visited)
-				if !ingValid {
-					allIngredientsValid = false
-				}
-			}
-		}
-	}
-
-	return allIngredientsValid
-}
-
-func convertPathToTree(path []model.Node, targetElement string, elements map[string]model.Element, baseElements []string) map[string]interface{} {
-	if len(path) == 0 {
-		return nil
-	}
-
-	var targetNode *model.Node
-	for i := range path {
-		if path[i].Element == targetElement {
-			targetNode = &path[i]
-			break
-		}
-	}
-
-	if targetNode == nil {
-		return nil
-	}
-
-	nodeMap := make(map[string]*model.Node)
-	positionNodeMap := make(map[string]map[int]*model.Node) // Track nodes by position
-
-	for i := range path {
-		nodeMap[path[i].Element] = &path[i]
-
-		if path[i].Position != 0 {
-			elemKey := path[i].Element
-			if positionNodeMap[elemKey] == nil {
-				positionNodeMap[elemKey] = make(map[int]*model.Node)
-			}
-			positionNodeMap[elemKey][path[i].Position] = &path[i]
-		}
-	}
-
-	processedInBranch := make(map[string]bool)
-	validityCache := make(map[string]bool)
-
-	isElementMakeable := func(element string) bool {
-		for _, base := range baseElements {
-			if element == base {
-				return true
-			}
-		}
-
-		if result, ok := validityCache[element]; ok {
-			return result
-		}
-
-		elemData, exists := elements[element]
-		if !exists {
-			validityCache[element] = false
-			return false
-		}
-
-		if len(elemData.Recipes) == 0 {
-			validityCache[element] = false
-			return false
-		}
-
-		validityCache[element] = true
-		return true
-	}
-
-	var buildTree func(element string, depth int) map[string]interface{}
-	buildTree = func(element string, depth int) map[string]interface{} {
-		if processedInBranch[element] {
-			return map[string]interface{}{
-				"name":                element,
-				"isCircularReference": true,
-				"ingredients":         []interface{}{},
-			}
-		}
-
-		if posMap, exists := positionNodeMap[element]; exists && len(posMap) > 0 {
-			log.Printf("DEBUG: Using position-specific recipe for %s", element)
-		}
-
-		if !isElementMakeable(element) && !processedInBranch[element] {
-			elemData, exists := elements[element]
-			if exists {
-				return map[string]interface{}{
-					"name":        element,
-					"imagePath":   elemData.ImagePath,
-					"unmakeable":  true,
-					"ingredients": []interface{}{},
-				}
-			}
-			return nil
-		}
-
-		processedInBranch[element] = true
-		defer func() {
-			delete(processedInBranch, element)
-		}()
-
-		node, found := nodeMap[element]
-		if !found {
-			elemData, exists := elements[element]
-			if !exists {
-				return nil
-			}
-
-			isBase := false
-			for _, base := range baseElements {
-				if element == base {
-					isBase = true
-					break
-				}
-			}
-
-			treeNode := map[string]interface{}{
-				"name":          element,
-				"imagePath":     elemData.ImagePath,
-				"isBaseElement": isBase,
-				"ingredients":   []interface{}{},
-			}
-
-			if !isBase && depth < 10 && len(elemData.Recipes) > 0 {
-				recipe := elemData.Recipes[0]
-				for _, ingredient := range recipe.Ingredients {
-					subtree := buildTree(ingredient, depth+1)
-					if subtree != nil {
-						treeNode["ingredients"] = append(treeNode["ingredients"].([]interface{}), subtree)
-					}
-				}
-			}
-
-			return treeNode
-		}
-
-		isBase := false
-		for _, base := range baseElements {
-			if element == base {
-				isBase = true
-				break
-			}
-		}
-
-		treeNode := map[string]interface{}{
-			"name":        element,
-			"imagePath":   node.ImagePath,
-			"ingredients": []interface{}{},
-		}
-
-		if isBase {
-			treeNode["isBaseElement"] = true
-			return treeNode
-		}
-
-		ingredients := node.Ingredients
-		if len(ingredients) == 0 {
-			if elemData, exists := elements[element]; exists && len(elemData.Recipes) > 0 {
-				ingredients = elemData.Recipes[0].Ingredients
-			}
-		}
-
-		if depth < 10 {
-			for _, ingredient := range ingredients {
-				subtree := buildTree(ingredient, depth+1)
-				if subtree != nil {
-					for i := 0; i < len(ingredients); i++ {
-						if ingredients[i] == ingredient && i > 0 {
-							subtree["pathIndex"] = i
-							subtree["ingredientIndex"] = i
-							break
-						}
-					}
-					treeNode["ingredients"] = append(treeNode["ingredients"].([]interface{}), subtree)
-				}
-			}
-		}
-
-		return treeNode
-	}
-
-	return buildTree(targetElement, 0)
-}
-
-func isTreeFullyMakeable(tree map[string]interface{}) bool {
-	if unmakeable, ok := tree["unmakeable"].(bool); ok && unmakeable {
-		elementName, _ := tree["name"].(string)
-		log.Printf("DEBUG: Tree node %s is unmakeable, rejecting tree", elementName)
-		return false
-	}
-
-	elementName, _ := tree["name"].(string)
-	ingredients, hasIngredients := tree["ingredients"].([]interface{})
-	isBase, hasBase := tree["isBaseElement"].(bool)
-
-	if (!hasBase || !isBase) && (!hasIngredients || len(ingredients) == 0) {
-		log.Printf("DEBUG: Non-base element %s has no ingredients, marking as unmakeable", elementName)
-		tree["unmakeable"] = true
-		return false
-	}
-
-	if hasIngredients {
-		for _, ing := range ingredients {
-			ingredient, ok := ing.(map[string]interface{})
-			if !ok {
-				continue
-			}
-
-			if !isTreeFullyMakeable(ingredient) {
-				log.Printf("DEBUG: Tree node %s has unmakeable ingredient, rejecting tree", elementName)
-				return false
-			}
-		}
-	}
-
-	return true
-}
-
 func (h *Handler) HandleDFSTree(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 
-	pathParts := strings.Split(strings.TrimPrefix(r.URL.Path, "/api/dfs-tree/"), "/")
-	if len(pathParts) < 1 {
+	// nama elemen boleh mengandung "/", jadi seluruh sisa path dipakai
+	elementName, ok := strings.CutPrefix(r.URL.Path, "/api/dfs-tree/")
+	if !ok {
 		http.Error(w, "Invalid URL format. Use /api/dfs-tree/{elementName}?count=N", http.StatusBadRequest)
 		return
 	}
-	elementName := strings.Join(pathParts, "/")
 
 	count := -1
 	if countParam := r.URL.Query().Get("count"); countParam != "" {
 		if countParam == "all" {
 			count = -1 
 		} else if parsedCount, err := strconv.Atoi(countParam); err == nil && parsedCount > 0 {
-			count = parsedCount
+			count = min(parsedCount, maxTreeCount)
+		}
+	}
+
+	// default tetap multithreaded, multithreaded=false dipakai kalo hasilnya
+	// harus sama setiap run (misal golden test)
+	useMultithreaded := true
+	if mtParam := r.URL.Query().Get("multithreaded"); mtParam != "" {
+		useMultithreaded = mtParam == "true"
+		log.Printf("DEBUG: Multithreaded mode: %v", useMultithreaded)
+	}
+	search := alg.MultiThreadedDFSWithMonitor
+	if !useMultithreaded {
+		search = func(elements map[string]model.Element, target string, maxResults int, _ bool, monitor *alg.Monitor) ([][]model.Node, int) {
+			return alg.DFSWithMonitor(elements, target, maxResults, false, monitor)
 		}
 	}
 
 	element, exists := h.elements[elementName]
 	if !exists {
-		http.Error(w, "Element not found", http.StatusNotFound)
+		h.writeElementNotFound(w, elementName)
 		log.Printf("DEBUG: Element '%s' not found in database", elementName)
 		return
 	}
 
+	if format := streamFormat(r); format != "" {
+		searchPathCount := 1000
+		if count > 0 {
+			searchPathCount = count * 20
+		}
+		h.streamSearch(w, r, format, elementName, "dfs", count, func(monitor *alg.Monitor) ([][]model.Node, int) {
+			return search(h.elements, elementName, searchPathCount, false, monitor)
+		})
+		return
+	}
+
 	baseElements := []string{"Water", "Fire", "Earth", "Air"}
 	isBaseElement := false
 	for _, base := range baseElements {
@@ -673,7 +428,7 @@ func (h *Handler) HandleDFSTree(w http.ResponseWriter, r *http.Request) {
 		searchPathCount = count * 20 
 	}
 
-	paths, visitedCount := alg.MultiThreadedDFS(h.elements, elementName, searchPathCount, false)
+	paths, visitedCount := search(h.elements, elementName, searchPathCount, false, nil)
 
 	log.Printf("DEBUG: DFS found %d paths after visiting %d nodes", len(paths), visitedCount)
 
@@ -682,14 +437,14 @@ func (h *Handler) HandleDFSTree(w http.ResponseWriter, r *http.Request) {
 	recipeSignatures := make(map[string]bool)
 
 	for _, path := range paths {
-		tree := convertPathToTree(path, elementName, h.elements, baseElements)
+		tree := utils.ConvertPathToTree(path, elementName, h.elements, baseElements)
 
 		if tree != nil {
-			ensureIngredientsExpanded(tree, h.elements, baseElements, make(map[string]bool))
+			utils.EnsureIngredientsExpanded(tree, h.elements, baseElements, make(map[string]bool))
 
 			recipeSig := getTopLevelRecipeSignature(tree)
 
-			signature := generateDetailedTreeSignature(tree)
+			signature := utils.DetailedTreeSignature(tree)
 			if !uniqueSignatures[signature] {
 				uniqueSignatures[signature] = true
 				recipeSignatures[recipeSig] = true
@@ -769,7 +524,7 @@ func (h *Handler) HandleDFSTree(w http.ResponseWriter, r *http.Request) {
 					for _, path := range paths {
 						subPath := extractSubPath(path, ingredient)
 						if subPath != nil {
-							subTree := convertPathToTree(subPath, ingredient, h.elements, baseElements)
+							subTree := utils.ConvertPathToTree(subPath, ingredient, h.elements, baseElements)
 							if subTree != nil {
 								ingTree = subTree
 								found = true
@@ -817,9 +572,9 @@ func (h *Handler) HandleDFSTree(w http.ResponseWriter, r *http.Request) {
 				tree["ingredients"] = append(tree["ingredients"].([]interface{}), ingTree)
 			}
 
-			ensureIngredientsExpanded(tree, h.elements, baseElements, make(map[string]bool))
+			utils.EnsureIngredientsExpanded(tree, h.elements, baseElements, make(map[string]bool))
 			recipeSig := getTopLevelRecipeSignature(tree)
-			signature := generateDetailedTreeSignature(tree)
+			signature := utils.DetailedTreeSignature(tree)
 
 			if !uniqueSignatures[signature] {
 				uniqueSignatures[signature] = true
@@ -909,7 +664,7 @@ func (h *Handler) HandleDFSTree(w http.ResponseWriter, r *http.Request) {
 				visited := make(map[string]bool)
 				ensureIngredientsRandomlyExpanded(tree, h.elements, baseElements, visited, i)
 
-				signature := generateDetailedTreeSignature(tree)
+				signature := utils.DetailedTreeSignature(tree)
 				recipeSig := getTopLevelRecipeSignature(tree)
 
 				if !uniqueSignatures[signature] {
@@ -954,7 +709,7 @@ func (h *Handler) HandleDFSTree(w http.ResponseWriter, r *http.Request) {
 		if len(selectedTrees) < count {
 			existingSigs := make(map[string]bool)
 			for _, tree := range selectedTrees {
-				sig := generateDetailedTreeSignature(tree)
+				sig := utils.DetailedTreeSignature(tree)
 				existingSigs[sig] = true
 			}
 
@@ -963,7 +718,7 @@ func (h *Handler) HandleDFSTree(w http.ResponseWriter, r *http.Request) {
 					break
 				}
 
-				sig := generateDetailedTreeSignature(tree)
+				sig := utils.DetailedTreeSignature(tree)
 				if !existingSigs[sig] {
 					existingSigs[sig] = true
 					selectedTrees = append(selectedTrees, tree)
@@ -982,7 +737,7 @@ func (h *Handler) HandleDFSTree(w http.ResponseWriter, r *http.Request) {
 			log.Printf("DEBUG: Filtering out untraceable tree in final check")
 		}
 	}
-	trees = traceableTrees
+	trees = h.rejectInvalidTrees("dfs tree", elementName, traceableTrees)
 
 	totalNodeCount := 0
 	for _, tree := range trees {
@@ -1016,7 +771,6 @@ func min(a, b int) int {
 	return b
 }
 
-
 func isTreeFullyTraceable(tree map[string]interface{}, baseElements []string, elements map[string]model.Element) bool {
 	if unmakeable, ok := tree["unmakeable"].(bool); ok && unmakeable {
 		return false
@@ -1125,71 +879,22 @@ func getTreeComplexityScore(tree map[string]interface{}) int {
 	return (nonBaseCount * 10) - baseCount + ingredientComplexity
 }
 
-
-func generateDetailedTreeSignature(tree map[string]interface{}) string {
-	var sb strings.Builder
-	sb.WriteString(tree["name"].(string))
-	if pos, ok := tree["position"].(int); ok && pos > 0 {
-		sb.WriteString(fmt.Sprintf("#%d", pos))
-	}
-
-	sb.WriteString(":")
-
-	ingredients, ok := tree["ingredients"].([]interface{})
-	if !ok || len(ingredients) == 0 {
-		return sb.String() + "[]"
-	}
-
-	elementName, _ := tree["name"].(string)
-	preserveOrder := (elementName == "Planet" || elementName == "Continent") ||
-		(len(ingredients) >= 2 && ingredients[0].(map[string]interface{})["name"] == ingredients[1].(map[string]interface{})["name"])
-
-	ingredientSignatures := make([]string, 0, len(ingredients))
-
-	for i, ing := range ingredients {
-		ingredient, ok := ing.(map[string]interface{})
-		if !ok {
-			continue
-		}
-
-		ingredientSig := generateDetailedTreeSignature(ingredient)
-
-		if i > 0 && ingredient["name"] == ingredients[i-1].(map[string]interface{})["name"] {
-			ingredientSig = fmt.Sprintf("%d:%s", i, ingredientSig)
-		} else if preserveOrder {
-			ingredientSig = fmt.Sprintf("%d:%s", i, ingredientSig)
-		}
-
-		ingredientSignatures = append(ingredientSignatures, ingredientSig)
-	}
-
-	if !preserveOrder && len(ingredientSignatures) > 1 {
-		sort.Strings(ingredientSignatures)
-	}
-
-	sb.WriteString("[")
-	sb.WriteString(strings.Join(ingredientSignatures, ","))
-	sb.WriteString("]")
-
-	return sb.String()
-}
-
 func (h *Handler) HandleBidirectionalSearch(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 
-	pathParts := strings.Split(strings.TrimPrefix(r.URL.Path, "/api/bidirectional/"), "/")
-	if len(pathParts) < 1 {
+	// nama elemen boleh mengandung "/", jadi seluruh sisa path dipakai
+	elementName, ok := strings.CutPrefix(r.URL.Path, "/api/bidirectional/")
+	if !ok {
 		http.Error(w, "Invalid URL format. Use /api/bidirectional/{elementName}", http.StatusBadRequest)
 		return
 	}
-	elementName := strings.Join(pathParts, "/")
 
 	log.Printf("DEBUG: Bidirectional search request for element: %s", elementName)
 
 	count := 3
 	if countParam := r.URL.Query().Get("count"); countParam != "" {
 		if parsedCount, err := strconv.Atoi(countParam); err == nil && parsedCount > 0 {
-			count = parsedCount
+			count = min(parsedCount, maxTreeCount)
 			log.Printf("DEBUG: Requested result count: %d", count)
 		}
 	}
@@ -1214,11 +919,25 @@ func (h *Handler) HandleBidirectionalSearch(w http.ResponseWriter, r *http.Reque
 
 	element, exists := h.elements[elementName]
 	if !exists {
-		http.Error(w, "Element not found", http.StatusNotFound)
+		h.writeElementNotFound(w, elementName)
 		log.Printf("DEBUG: Element '%s' not found in database", elementName)
 		return
 	}
 
+	if format := streamFormat(r); format != "" {
+		algoName := "bidirectional"
+		if useMultithreaded {
+			algoName = "multithreaded-bidirectional"
+		}
+		h.streamSearch(w, r, format, elementName, algoName, count, func(monitor *alg.Monitor) ([][]model.Node, int) {
+			if useMultithreaded {
+				return alg.MultiThreadedBidirectionalBFSWithMonitor(h.elements, elementName, count*20, singlePath, monitor)
+			}
+			return alg.BidirectionalBFSWithMonitor(h.elements, elementName, count*20, singlePath, monitor)
+		})
+		return
+	}
+
 	baseElements := []string{"Water", "Fire", "Earth", "Air"}
 	isBaseElement := false
 	for _, base := range baseElements {
@@ -1344,7 +1063,9 @@ func (h *Handler) HandleBidirectionalSearch(w http.ResponseWriter, r *http.Reque
 			recipeKeys = append(recipeKeys, key)
 		}
 
-		sort.Slice(recipeKeys, func(i, j int) bool {
+		// urutkan nama dulu supaya urutan resep tidak tergantung iterasi map
+		sort.Strings(recipeKeys)
+		sort.SliceStable(recipeKeys, func(i, j int) bool {
 			return len(strings.Split(recipeKeys[i], "+")) < len(strings.Split(recipeKeys[j], "+"))
 		})
 
@@ -1356,16 +1077,19 @@ func (h *Handler) HandleBidirectionalSearch(w http.ResponseWriter, r *http.Reque
 			treeCreated := false
 
 			if len(recipePaths) > 0 {
-				sort.Slice(recipePaths, func(i, j int) bool {
+				sort.SliceStable(recipePaths, func(i, j int) bool {
 					return len(recipePaths[i]) < len(recipePaths[j])
 				})
 
 				for _, path := range recipePaths {
-					tree := convertPathToTree(path, elementName, h.elements, baseElements)
+					tree := utils.ConvertPathToTree(path, elementName, h.elements, baseElements)
 
 					if tree != nil {
-						ensureIngredientsExpanded(tree, h.elements, baseElements, make(map[string]bool))
-						signature := generateDetailedTreeSignature(tree)
+						utils.EnsureIngredientsExpanded(tree, h.elements, baseElements, make(map[string]bool))
+						if h.verifyTrees && !utils.IsTreeFullyMakeable(tree) {
+							continue
+						}
+						signature := utils.DetailedTreeSignature(tree)
 
 						if !uniqueSignatures[signature] {
 							uniqueSignatures[signature] = true
@@ -1430,7 +1154,7 @@ func (h *Handler) HandleBidirectionalSearch(w http.ResponseWriter, r *http.Reque
 							}
 
 							if ingPath != nil {
-								ingSubTree := convertPathToTree(ingPath, ingredient, h.elements, baseElements)
+								ingSubTree := utils.ConvertPathToTree(ingPath, ingredient, h.elements, baseElements)
 								if ingSubTree != nil {
 									ingTree = ingSubTree
 								}
@@ -1440,10 +1164,12 @@ func (h *Handler) HandleBidirectionalSearch(w http.ResponseWriter, r *http.Reque
 						tree["ingredients"] = append(tree["ingredients"].([]interface{}), ingTree)
 					}
 
-					ensureIngredientsExpanded(tree, h.elements, baseElements, make(map[string]bool))
+					utils.EnsureIngredientsExpanded(tree, h.elements, baseElements, make(map[string]bool))
 
-					signature := generateDetailedTreeSignature(tree)
-					if !uniqueSignatures[signature] {
+					signature := utils.DetailedTreeSignature(tree)
+					if h.verifyTrees && !utils.IsTreeFullyMakeable(tree) {
+						log.Printf("DEBUG: Manual tree for recipe %s is not makeable, skipping", recipeKey)
+					} else if !uniqueSignatures[signature] {
 						uniqueSignatures[signature] = true
 						trees = append(trees, tree)
 						log.Printf("DEBUG: Added manual tree for recipe: %s (tree count: %d)", recipeKey, len(trees))
@@ -1509,8 +1235,12 @@ func (h *Handler) HandleBidirectionalSearch(w http.ResponseWriter, r *http.Reque
 
 				visited := make(map[string]bool)
 				ensureIngredientsRandomlyExpanded(tree, h.elements, baseElements, visited, len(trees))
+				if h.verifyTrees && !utils.IsTreeFullyMakeable(tree) {
+					log.Printf("DEBUG: Variation tree for %v is not makeable, skipping", recipe.Ingredients)
+					continue
+				}
 
-				signature := generateDetailedTreeSignature(tree)
+				signature := utils.DetailedTreeSignature(tree)
 				if !uniqueSignatures[signature] {
 					uniqueSignatures[signature] = true
 					trees = append(trees, tree)
@@ -1520,6 +1250,7 @@ func (h *Handler) HandleBidirectionalSearch(w http.ResponseWriter, r *http.Reque
 		}
 
 		log.Printf("DEBUG: Final tree count: %d", len(trees))
+		trees = h.rejectInvalidTrees(algoName, elementName, trees)
 
 		totalNodeCount := 0
 		for _, tree := range trees {
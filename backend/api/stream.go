@@ -0,0 +1,194 @@
+package api
+
+import (
+	alg "backend/internal/algorithm"
+	"backend/model"
+	"backend/utils"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	streamNDJSON = "ndjson"
+	streamSSE    = "sse"
+)
+
+// streamFormat mengembalikan format streaming yang diminta client lewat
+// ?stream=ndjson|sse atau header Accept: text/event-stream, "" kalo tidak streaming.
+func streamFormat(r *http.Request) string {
+	switch r.URL.Query().Get("stream") {
+	case "ndjson", "true":
+		return streamNDJSON
+	case "sse":
+		return streamSSE
+	}
+	if strings.Contains(r.Header.Get("Accept"), "text/event-stream") {
+		return streamSSE
+	}
+	return ""
+}
+
+// treeStream menulis event ke client dan langsung di-flush
+type treeStream struct {
+	w       http.ResponseWriter
+	flusher http.Flusher
+	format  string
+
+	mu     sync.Mutex
+	closed bool
+}
+
+func newTreeStream(w http.ResponseWriter, format string) *treeStream {
+	if format == streamSSE {
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("Cache-Control", "no-cache")
+	} else {
+		w.Header().Set("Content-Type", "application/x-ndjson")
+	}
+	// supaya proxy seperti nginx tidak menahan response
+	w.Header().Set("X-Accel-Buffering", "no")
+
+	flusher, _ := w.(http.Flusher)
+	return &treeStream{w: w, flusher: flusher, format: format}
+}
+
+func (s *treeStream) send(event string, data map[string]interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return
+	}
+
+	// SSE membawa nama event di baris "event:", NDJSON di field "event"
+	if s.format != streamSSE {
+		data["event"] = event
+	}
+	payload, err := json.Marshal(data)
+	if err != nil {
+		log.Printf("ERROR: Failed to encode %s event: %v", event, err)
+		return
+	}
+
+	if s.format == streamSSE {
+		_, err = fmt.Fprintf(s.w, "event: %s\ndata: %s\n\n", event, payload)
+	} else {
+		_, err = fmt.Fprintf(s.w, "%s\n", payload)
+	}
+	if err != nil {
+		// client sudah putus, sisa event dibuang
+		s.closed = true
+		return
+	}
+
+	if s.flusher != nil {
+		s.flusher.Flush()
+	}
+}
+
+func (s *treeStream) close() {
+	s.mu.Lock()
+	s.closed = true
+	s.mu.Unlock()
+}
+
+// streamSearch menjalankan search dan mengirim setiap pohon resep unik yang bisa
+// dibuat begitu ditemukan. Pencarian dihentikan setelah count pohon terkirim
+// (count <= 0 berarti semua) atau kalo client putus. Event terakhir "done"
+// berisi ringkasan seperti response biasa.
+func (h *Handler) streamSearch(w http.ResponseWriter, r *http.Request, format, elementName, algoName string, count int, search func(monitor *alg.Monitor) ([][]model.Node, int)) {
+	stream := newTreeStream(w, format)
+	baseElements := []string{"Water", "Fire", "Earth", "Air"}
+
+	if utils.IsBaseElementName(elementName, baseElements) {
+		stream.send("tree", map[string]interface{}{
+			"index": 0,
+			"tree": map[string]interface{}{
+				"name":          elementName,
+				"imagePath":     h.elements[elementName].ImagePath,
+				"ingredients":   []interface{}{},
+				"isBaseElement": true,
+			},
+		})
+		stream.send("done", map[string]interface{}{
+			"algorithm":    algoName,
+			"nodesVisited": 1,
+			"timeElapsed":  0,
+			"totalTrees":   1,
+		})
+		return
+	}
+
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+
+	var mu sync.Mutex
+	sent := 0
+	totalTreeNodes := 0
+	seen := make(map[string]bool)
+	emit := func(path []model.Node) {
+		mu.Lock()
+		defer mu.Unlock()
+		if count > 0 && sent >= count {
+			return
+		}
+
+		tree := utils.ConvertPathToTree(path, elementName, h.elements, baseElements)
+		if tree == nil {
+			return
+		}
+		utils.EnsureIngredientsExpanded(tree, h.elements, baseElements, make(map[string]bool))
+		if !utils.IsTreeFullyMakeable(tree) || !h.treeIsValid(algoName+" stream", elementName, tree) {
+			return
+		}
+
+		signature := utils.DetailedTreeSignature(tree)
+		if seen[signature] {
+			return
+		}
+		seen[signature] = true
+
+		stream.send("tree", map[string]interface{}{"index": sent, "tree": tree})
+		sent++
+		totalTreeNodes += countNodesInTree(tree)
+
+		if count > 0 && sent >= count {
+			log.Printf("DEBUG: Streamed %d trees for %s, stopping search", sent, elementName)
+			cancel()
+		}
+	}
+
+	log.Printf("DEBUG: Streaming %s search for '%s' as %s", algoName, elementName, format)
+	startTime := time.Now()
+	monitor := alg.NewMonitor(ctx, emit)
+	paths, visitedCount := search(monitor)
+
+	// hasil akhir bisa berisi path yang belum pernah dilaporkan (misal hasil
+	// post-processing), kirim juga kalo masih ada tempat
+	for _, path := range paths {
+		emit(path)
+	}
+
+	mu.Lock()
+	summary := map[string]interface{}{
+		"algorithm":      algoName,
+		"nodesVisited":   visitedCount,
+		"timeElapsed":    time.Since(startTime).Milliseconds(),
+		"totalTrees":     sent,
+		"totalTreeNodes": totalTreeNodes,
+	}
+	mu.Unlock()
+
+	if r.Context().Err() != nil {
+		log.Printf("DEBUG: Client disconnected while streaming '%s'", elementName)
+		stream.close()
+		return
+	}
+	stream.send("done", summary)
+	stream.close()
+}
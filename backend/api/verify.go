@@ -0,0 +1,51 @@
+package api
+
+import (
+	"backend/utils"
+	"log"
+	"os"
+)
+
+// verifyTreesFromEnv menyalakan mode debug VERIFY_TREES=true, di mode ini
+// setiap pohon diperiksa dengan utils.VerifyTree sebelum dikirim
+func verifyTreesFromEnv() bool {
+	value := os.Getenv("VERIFY_TREES")
+	return value == "true" || value == "1"
+}
+
+// rejectInvalidTrees membuang pohon yang tidak bisa dibuat dari dataset dan
+// mencatat alasannya. Tanpa mode debug trees dikembalikan apa adanya.
+func (h *Handler) rejectInvalidTrees(source, elementName string, trees []map[string]interface{}) []map[string]interface{} {
+	if !h.verifyTrees {
+		return trees
+	}
+	valid := make([]map[string]interface{}, 0, len(trees))
+	for _, tree := range trees {
+		if h.treeIsValid(source, elementName, tree) {
+			valid = append(valid, tree)
+		}
+	}
+	return valid
+}
+
+// treeIsValid memeriksa satu pohon, selalu true kalo mode debug mati
+func (h *Handler) treeIsValid(source, elementName string, tree map[string]interface{}) bool {
+	if !h.verifyTrees {
+		return true
+	}
+	// pohon pengganti untuk elemen yang tidak bisa dibuat bukan resep, jadi tidak dicek
+	if _, isNotice := tree["notice"]; isNotice {
+		return true
+	}
+
+	baseElements := []string{"Water", "Fire", "Earth", "Air"}
+	issues := utils.VerifyTree(tree, elementName, h.elements, baseElements)
+	if len(issues) == 0 {
+		return true
+	}
+	log.Printf("ERROR: %s returned an invalid tree for %s (%d issues), rejecting it", source, elementName, len(issues))
+	for _, issue := range issues {
+		log.Printf("ERROR:   %s", issue)
+	}
+	return false
+}
@@ -0,0 +1,153 @@
+package api
+
+import (
+	alg "backend/internal/algorithm"
+	"backend/utils"
+	"context"
+	"encoding/json"
+	"flag"
+	"math/rand"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+)
+
+var (
+	verifySeed    = flag.Int64("verify.seed", 0, "seed untuk target acak di TestTreesAreCraftable, 0 berarti pakai waktu sekarang")
+	verifyTargets = flag.Int("verify.targets", 12, "jumlah target acak per algoritma di TestTreesAreCraftable")
+)
+
+// batas waktu satu pencarian di property test, elemen tier tinggi bisa lama
+// untuk BFS biasa
+const verifySearchTimeout = 5 * time.Second
+
+// randomTargets mengambil n elemen yang bisa dibuat (bukan elemen dasar)
+// secara acak. Seed dicatat supaya kegagalan bisa diulang dengan -verify.seed.
+func randomTargets(t *testing.T, h *Handler, n int) []string {
+	t.Helper()
+	seed := *verifySeed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	t.Logf("random targets with -verify.seed=%d", seed)
+	rng := rand.New(rand.NewSource(seed))
+
+	baseElements := []string{"Water", "Fire", "Earth", "Air"}
+	candidates := make([]string, 0, len(h.names))
+	for _, name := range h.names {
+		if h.reachable[name] && !utils.IsBaseElementName(name, baseElements) {
+			candidates = append(candidates, name)
+		}
+	}
+	if testing.Short() && n > 4 {
+		n = 4
+	}
+
+	targets := make([]string, n)
+	for i := range targets {
+		targets[i] = candidates[rng.Intn(len(candidates))]
+	}
+	return targets
+}
+
+// TestTreesAreCraftable memastikan setiap pohon yang dibangun dari hasil
+// setiap algoritma bisa benar-benar dibuat dari dataset
+func TestTreesAreCraftable(t *testing.T) {
+	h := newGoldenHandler(t)
+	targets := randomTargets(t, h, *verifyTargets)
+	baseElements := []string{"Water", "Fire", "Earth", "Air"}
+
+	for _, algorithm := range alg.Algorithms() {
+		t.Run(algorithm.Name, func(t *testing.T) {
+			for _, target := range targets {
+				ctx, cancel := context.WithTimeout(context.Background(), verifySearchTimeout)
+				paths, _ := algorithm.SearchWithMonitor(h.elements, target, 20, false, alg.NewMonitor(ctx, nil))
+				cancel()
+
+				for i, tree := range BuildTrees(h.elements, paths, target, 0) {
+					for _, issue := range utils.VerifyTree(tree, target, h.elements, baseElements) {
+						t.Errorf("%s tree %d: %s", target, i, issue)
+					}
+				}
+			}
+		})
+	}
+}
+
+// TestHandlerTreesAreCraftable memeriksa pohon yang benar-benar dikirim oleh
+// endpoint pohon resep
+func TestHandlerTreesAreCraftable(t *testing.T) {
+	h := newGoldenHandler(t)
+	targets := randomTargets(t, h, *verifyTargets)
+	baseElements := []string{"Water", "Fire", "Earth", "Air"}
+
+	endpoints := []struct {
+		name   string
+		prefix string
+		query  string
+	}{
+		{"bfs", "/api/bfs-tree/", "count=3"},
+		{"dfs", "/api/dfs-tree/", "count=3"},
+		{"bidirectional", "/api/bidirectional/", "count=3&tree=true"},
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/bfs-tree/", h.HandleBFSTree)
+	mux.HandleFunc("/api/dfs-tree/", h.HandleDFSTree)
+	mux.HandleFunc("/api/bidirectional/", h.HandleBidirectionalSearch)
+
+	for _, endpoint := range endpoints {
+		t.Run(endpoint.name, func(t *testing.T) {
+			for _, target := range targets {
+				target := (&url.URL{Path: target}).EscapedPath()
+				recorder := httptest.NewRecorder()
+				mux.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, endpoint.prefix+target+"?"+endpoint.query, nil))
+
+				var response struct {
+					Trees []map[string]interface{} `json:"trees"`
+				}
+				if err := json.Unmarshal(recorder.Body.Bytes(), &response); err != nil {
+					t.Fatalf("%s: invalid response: %v", target, err)
+				}
+				name, _ := url.PathUnescape(target)
+				for i, tree := range response.Trees {
+					for _, issue := range utils.VerifyTree(tree, name, h.elements, baseElements) {
+						t.Errorf("%s tree %d: %s", name, i, issue)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestRejectInvalidTrees(t *testing.T) {
+	h := newGoldenHandler(t)
+	valid := map[string]interface{}{
+		"name": "Steam",
+		"ingredients": []interface{}{
+			map[string]interface{}{"name": "Water"},
+			map[string]interface{}{"name": "Fire"},
+		},
+	}
+	invalid := map[string]interface{}{
+		"name": "Steam",
+		"ingredients": []interface{}{
+			map[string]interface{}{"name": "Water"},
+			map[string]interface{}{"name": "Water"},
+		},
+	}
+	notice := map[string]interface{}{"name": "Steam", "ingredients": []interface{}{}, "notice": "cannot be traced"}
+	trees := []map[string]interface{}{valid, invalid, notice}
+
+	if got := h.rejectInvalidTrees("test", "Steam", trees); len(got) != 3 {
+		t.Fatalf("debug mode is off, expected all 3 trees, got %d", len(got))
+	}
+
+	h.verifyTrees = true
+	got := h.rejectInvalidTrees("test", "Steam", trees)
+	if len(got) != 2 || got[0]["ingredients"].([]interface{})[1].(map[string]interface{})["name"] != "Fire" {
+		t.Fatalf("expected the valid and notice trees, got %v", got)
+	}
+}
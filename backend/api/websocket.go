@@ -1,421 +1,251 @@
 package api
 
 import (
-	"backend/internal/algorithm"
+	alg "backend/internal/algorithm"
+	"backend/internal/recordings"
 	"backend/model"
-	"encoding/json"
+	"backend/utils"
+	"errors"
 	"log"
 	"net/http"
-	"net/url"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/gorilla/websocket"
 )
 
-type AnimationStep struct {
-	StepIndex   int             `json:"stepIndex"`
-	TotalSteps  int             `json:"totalSteps"`
-	Node        json.RawMessage `json:"node,omitempty"`
-	Link        json.RawMessage `json:"link,omitempty"`
-	IsBaseNode  bool            `json:"isBaseNode"`
-	IsCompleted bool            `json:"isCompleted"`
-	Type        string          `json:"type"`
-}
-
-func (h *Handler) HandleAnimationWebSocket(w http.ResponseWriter, r *http.Request) {
-	urlParts := strings.Split(r.URL.Path, "/")
-	if len(urlParts) < 4 {
-		http.Error(w, "Invalid URL format", http.StatusBadRequest)
-		return
-	}
+const (
+	animationStepDelay = 50 * time.Millisecond
+	// batas event yang disimpan per pencarian, event sesudahnya dibuang
+	maxAnimationEvents = 5000
+)
 
-	targetElement := urlParts[len(urlParts)-1]
-	targetElement, _ = url.QueryUnescape(targetElement)
+// eventLog menampung event dari algoritma sampai dikirim ke client. Algoritma
+// tidak pernah menunggu client, jadi pencarian tetap secepat biasa walaupun
+// animasinya diputar pelan.
+type eventLog struct {
+	mu        sync.Mutex
+	events    []alg.SearchEvent
+	truncated bool
+	finished  bool
+	notify    chan struct{}
+}
 
-	algorithmType := r.URL.Query().Get("algorithm")
-	if algorithmType == "" {
-		algorithmType = "bfs" 
-	}
-	upgrader := websocket.Upgrader{
-		ReadBufferSize:  1024,
-		WriteBufferSize: 1024,
-		CheckOrigin: func(r *http.Request) bool {
-			return true 
-		},
-	}
+func newEventLog() *eventLog {
+	return &eventLog{notify: make(chan struct{}, 1)}
+}
 
-	conn, err := upgrader.Upgrade(w, r, nil)
-	if err != nil {
-		log.Printf("ERROR: Failed to upgrade to WebSocket: %v", err)
-		return
+func (l *eventLog) add(event alg.SearchEvent) {
+	l.mu.Lock()
+	if len(l.events) < maxAnimationEvents {
+		l.events = append(l.events, event)
+	} else {
+		l.truncated = true
 	}
-	defer conn.Close()
-
-	log.Printf("DEBUG: WebSocket connection established for %s using %s algorithm", targetElement, algorithmType)
-
-	conn.WriteJSON(map[string]interface{}{
-		"type":      "metadata",
-		"algorithm": algorithmType,
-		"element":   targetElement,
-	})
+	l.mu.Unlock()
+	l.wake()
+}
 
-	var animationSteps []map[string]interface{}
-	var visitedCount int
+func (l *eventLog) finish() {
+	l.mu.Lock()
+	l.finished = true
+	l.mu.Unlock()
+	l.wake()
+}
 
-	switch algorithmType {
-	case "bfs":
-		animationSteps, visitedCount = h.generateBFSAnimationSteps(targetElement)
-	case "dfs":
-		animationSteps, visitedCount = h.generateDFSAnimationSteps(targetElement)
-	case "bidirectional":
-		animationSteps, visitedCount = h.generateBidirectionalAnimationSteps(targetElement)
+func (l *eventLog) wake() {
+	select {
+	case l.notify <- struct{}{}:
 	default:
-		log.Printf("WARNING: Unknown algorithm %s, falling back to BFS", algorithmType)
-		animationSteps, visitedCount = h.generateBFSAnimationSteps(targetElement)
 	}
-
-	conn.WriteJSON(map[string]interface{}{
-		"type":       "steps",
-		"totalSteps": len(animationSteps),
-	})
-
-	for i, step := range animationSteps {
-		step["stepIndex"] = i + 1
-		step["totalSteps"] = len(animationSteps)
-
-		err := conn.WriteJSON(step)
-		if err != nil {
-			log.Printf("ERROR: Failed to send animation step: %v", err)
-			break
-		}
-
-		time.Sleep(50 * time.Millisecond)
-	}
-
-	conn.WriteJSON(map[string]interface{}{
-		"type":         "complete",
-		"nodesVisited": visitedCount,
-	})
-
-	log.Printf("DEBUG: Animation complete for %s, sent %d steps", targetElement, len(animationSteps))
 }
 
-func (h *Handler) generateBFSAnimationSteps(targetElement string) ([]map[string]interface{}, int) {
-	paths, visitedCount := algorithm.BFS(h.elements, targetElement, 1, true)
-
-	return h.convertBFSToAnimationSteps(paths), visitedCount
+func (l *eventLog) len() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return len(l.events)
 }
 
-func (h *Handler) generateDFSAnimationSteps(targetElement string) ([]map[string]interface{}, int) {
-	paths, visitedCount := algorithm.DFS(h.elements, targetElement, 1, true)
-
-	return h.convertDFSToAnimationSteps(paths), visitedCount
+// get mengembalikan event ke-i kalo sudah ada. done true kalo pencarian sudah
+// selesai dan tidak akan ada event ke-i.
+func (l *eventLog) get(i int) (event alg.SearchEvent, ok bool, done bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if i < len(l.events) {
+		return l.events[i], true, false
+	}
+	return alg.SearchEvent{}, false, l.finished
 }
 
-func (h *Handler) generateBidirectionalAnimationSteps(targetElement string) ([]map[string]interface{}, int) {
-	paths, visitedCount := algorithm.BidirectionalBFS(h.elements, targetElement, 1, true)
+type animationResult struct {
+	paths   [][]model.Node
+	visited int
+	elapsed time.Duration
+	err     string
 
-	return h.convertBidirectionalToAnimationSteps(paths), visitedCount
+	recording string
+	recordErr error
 }
 
-func (h *Handler) convertBFSToAnimationSteps(paths [][]model.Node) []map[string]interface{} {
-	if len(paths) == 0 {
-		return []map[string]interface{}{}
+// HandleAnimationWebSocket menjalankan pencarian dan mengirim setiap langkah
+// eksplorasi yang benar-benar dilakukan algoritma (enqueued, expanded, pruned,
+// frontier-met, path-found) ke client. Pemutarannya bisa dikontrol client,
+// lihat animationPlayer. /api/animation-ws/room/{id} dilayani HandleAnimationRoom.
+func (h *Handler) HandleAnimationWebSocket(w http.ResponseWriter, r *http.Request) {
+	if strings.HasPrefix(r.URL.Path, animationRoomPrefix) {
+		h.HandleAnimationRoom(w, r)
+		return
 	}
 
-	path := paths[0]
-	steps := []map[string]interface{}{}
-	baseElements := []string{"Water", "Fire", "Earth", "Air"}
-
-	nodesByLevel := make(map[int][]model.Node)
-	maxLevel := 0
-
-	for _, node := range path {
-		isBase := false
-		for _, base := range baseElements {
-			if node.Element == base {
-				isBase = true
-				break
-			}
-		}
-
-		if isBase {
-			nodesByLevel[0] = append(nodesByLevel[0], node)
-		}
+	// r.URL.Path sudah di-decode, jadi nama tidak di-unescape lagi (nama
+	// dengan "+" atau "%" bisa rusak) dan nama yang mengandung "/" tetap utuh,
+	// sama seperti handler bfs-tree, dfs-tree dan bidirectional
+	targetElement, ok := strings.CutPrefix(r.URL.Path, "/api/animation-ws/")
+	if !ok || targetElement == "" {
+		http.Error(w, "Invalid URL format. Use /api/animation-ws/{elementName}", http.StatusBadRequest)
+		return
 	}
 
-	for level := 0; level < len(path); level++ {
-		if _, exists := nodesByLevel[level]; !exists {
-			continue
-		}
-
-		for _, currentNode := range nodesByLevel[level] {
-			for _, node := range path {
-				if node.Ingredients != nil && containsElement(node.Ingredients, currentNode.Element) {
-					alreadyAssigned := false
-					for l := 0; l <= level; l++ {
-						for _, assignedNode := range nodesByLevel[l] {
-							if assignedNode.Element == node.Element {
-								alreadyAssigned = true
-								break
-							}
-						}
-						if alreadyAssigned {
-							break
-						}
-					}
+	h.serveAnimation(w, r, targetElement, nil)
+}
 
-					if !alreadyAssigned {
-						nodesByLevel[level+1] = append(nodesByLevel[level+1], node)
-						if level+1 > maxLevel {
-							maxLevel = level + 1
-						}
-					}
-				}
-			}
+// serveAnimation memvalidasi parameter, upgrade ke WebSocket lalu memutar
+// animasi sampai client putus. Kalo room tidak nil, semua pesan juga dikirim
+// ke viewer di room itu.
+func (h *Handler) serveAnimation(w http.ResponseWriter, r *http.Request, targetElement string, room *animationRoom) {
+	// rekaman sudah berisi elemen dan algoritmanya, elemen di URL diabaikan
+	recordingID := r.URL.Query().Get("recording")
+	var recording *recordings.Recording
+	if recordingID != "" {
+		rec, err := h.recordings.Load(recordingID)
+		if errors.Is(err, recordings.ErrNotFound) || errors.Is(err, recordings.ErrInvalidID) {
+			http.Error(w, "Recording not found", http.StatusNotFound)
+			return
 		}
+		if err != nil {
+			log.Printf("ERROR: Failed to load recording %s: %v", recordingID, err)
+			http.Error(w, "Failed to load recording", http.StatusInternalServerError)
+			return
+		}
+		recording = rec
+		targetElement = rec.Element
+	} else if _, exists := h.elements[targetElement]; !exists {
+		h.writeElementNotFound(w, targetElement)
+		return
 	}
 
-	for level := 0; level <= maxLevel; level++ {
-		for _, node := range nodesByLevel[level] {
-			isBase := false
-			for _, base := range baseElements {
-				if node.Element == base {
-					isBase = true
-					break
-				}
-			}
-
-			steps = append(steps, map[string]interface{}{
-				"type": "node",
-				"node": map[string]interface{}{
-					"name":      node.Element,
-					"imagePath": node.ImagePath,
-				},
-				"isBaseNode":  isBase,
-				"isCompleted": false,
-			})
-		}
+	algorithmType := r.URL.Query().Get("algorithm")
+	if algorithmType == "" {
+		algorithmType = "bfs"
 	}
-
-	for i := 0; i < len(path)-1; i++ {
-		for j := i + 1; j < len(path); j++ {
-			if j == i+1 || (path[j].Ingredients != nil && containsElement(path[j].Ingredients, path[i].Element)) {
-				steps = append(steps, map[string]interface{}{
-					"type": "link",
-					"link": map[string]interface{}{
-						"source": path[i].Element,
-						"target": path[j].Element,
-					},
-					"isBaseNode":  false,
-					"isCompleted": false,
-				})
-			}
-		}
+	if animationSearch(algorithmType) == nil {
+		log.Printf("WARNING: Unknown algorithm %s, falling back to BFS", algorithmType)
+		algorithmType = "bfs"
 	}
-
-	return steps
-}
-
-func (h *Handler) convertDFSToAnimationSteps(paths [][]model.Node) []map[string]interface{} {
-	if len(paths) == 0 {
-		return []map[string]interface{}{}
+	if recording != nil {
+		algorithmType = recording.Algorithm
 	}
 
-	path := paths[0]
-	steps := []map[string]interface{}{}
-	baseElements := []string{"Water", "Fire", "Earth", "Air"}
+	conn, ok := upgradeAnimation(w, r)
+	if !ok {
+		return
+	}
+	defer conn.Close()
 
-	for i := 0; i < len(path); i++ {
-		isBase := false
-		for _, base := range baseElements {
-			if path[i].Element == base {
-				isBase = true
-				break
-			}
-		}
+	log.Printf("DEBUG: WebSocket connection established for %s using %s algorithm", targetElement, algorithmType)
 
-		steps = append(steps, map[string]interface{}{
-			"type": "node",
-			"node": map[string]interface{}{
-				"name":      path[i].Element,
-				"imagePath": path[i].ImagePath,
-			},
-			"isBaseNode":  isBase,
-			"isCompleted": false,
-		})
+	player := newAnimationPlayer(h, conn)
+	player.room = room
+	defer player.stopSearch()
 
-		if i < len(path)-1 && path[i+1].Ingredients != nil &&
-			containsElement(path[i+1].Ingredients, path[i].Element) {
-			steps = append(steps, map[string]interface{}{
-				"type": "link",
-				"link": map[string]interface{}{
-					"source": path[i].Element,
-					"target": path[i+1].Element,
-				},
-				"isBaseNode":  false,
-				"isCompleted": false,
-			})
-		}
+	if r.URL.Query().Get("paused") == "true" {
+		player.paused = true
 	}
-
-	for i := 0; i < len(path)-1; i++ {
-		for j := i + 1; j < len(path); j++ {
-			if j == i+1 {
-				continue
-			}
-
-			if path[j].Ingredients != nil && containsElement(path[j].Ingredients, path[i].Element) {
-				steps = append(steps, map[string]interface{}{
-					"type": "link",
-					"link": map[string]interface{}{
-						"source": path[i].Element,
-						"target": path[j].Element,
-					},
-					"isBaseNode":  false,
-					"isCompleted": false,
-				})
-			}
-		}
+	var err error
+	if recording != nil {
+		err = player.replay(recordingID, recording)
+	} else {
+		err = player.start(targetElement, algorithmType, r.URL.Query().Get("record") == "true")
 	}
-
-	return steps
+	if err != nil {
+		log.Printf("ERROR: Failed to start animation for %s: %v", targetElement, err)
+		return
+	}
+	player.run()
 }
 
-func (h *Handler) convertBidirectionalToAnimationSteps(paths [][]model.Node) []map[string]interface{} {
-	if len(paths) == 0 {
-		return []map[string]interface{}{}
+// upgradeAnimation upgrade request ke WebSocket dengan negosiasi subprotocol.
+// ok false kalo upgrade gagal atau protokol client tidak didukung.
+func upgradeAnimation(w http.ResponseWriter, r *http.Request) (*websocket.Conn, bool) {
+	upgrader := websocket.Upgrader{
+		ReadBufferSize:  1024,
+		WriteBufferSize: 1024,
+		Subprotocols:    []string{AnimationSubprotocol},
+		CheckOrigin: func(r *http.Request) bool {
+			return true
+		},
 	}
 
-	path := paths[0]
-	steps := []map[string]interface{}{}
-	baseElements := []string{"Water", "Fire", "Earth", "Air"}
-
-	targetElement := ""
-	if len(path) > 0 {
-		targetElement = path[len(path)-1].Element
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		log.Printf("ERROR: Failed to upgrade to WebSocket: %v", err)
+		return nil, false
 	}
 
-	for i := len(path) - 1; i >= 0; i-- {
-		if path[i].Element == targetElement {
-			steps = append(steps, map[string]interface{}{
-				"type": "node",
-				"node": map[string]interface{}{
-					"name":      path[i].Element,
-					"imagePath": path[i].ImagePath,
-				},
-				"isBaseNode":  false,
-				"isCompleted": false,
-			})
-			break
-		}
+	// client menawarkan subprotocol tapi tidak ada yang kita dukung, kemungkinan
+	// versi protokolnya beda
+	if offered := websocket.Subprotocols(r); len(offered) > 0 && conn.Subprotocol() == "" {
+		log.Printf("WARNING: Rejecting animation client with unsupported protocols %v", offered)
+		conn.WriteControl(websocket.CloseMessage,
+			websocket.FormatCloseMessage(websocket.CloseProtocolError, "supported protocol: "+AnimationSubprotocol),
+			time.Now().Add(animationWriteWait))
+		conn.Close()
+		return nil, false
 	}
 
-	for _, node := range path {
-		isBase := false
-		for _, base := range baseElements {
-			if node.Element == base {
-				isBase = true
-				break
-			}
-		}
+	return conn, true
+}
 
-		if isBase {
-			steps = append(steps, map[string]interface{}{
-				"type": "node",
-				"node": map[string]interface{}{
-					"name":      node.Element,
-					"imagePath": node.ImagePath,
-				},
-				"isBaseNode":  true,
-				"isCompleted": false,
-			})
+// animationSearch mengembalikan pencarian satu path untuk algoritma animasi,
+// nil kalo tidak dikenal. Yang dipakai versi single-thread supaya urutan event
+// sama dengan urutan eksplorasi algoritmanya.
+func animationSearch(algorithmType string) func(elements map[string]model.Element, target string, monitor *alg.Monitor) ([][]model.Node, int) {
+	switch algorithmType {
+	case "bfs":
+		return func(elements map[string]model.Element, target string, monitor *alg.Monitor) ([][]model.Node, int) {
+			return alg.BFSWithMonitor(elements, target, 1, true, monitor)
 		}
-	}
-
-	addedNodes := make(map[string]bool)
-	for _, step := range steps {
-		if step["type"] == "node" {
-			nodeName := step["node"].(map[string]interface{})["name"].(string)
-			addedNodes[nodeName] = true
+	case "dfs":
+		return func(elements map[string]model.Element, target string, monitor *alg.Monitor) ([][]model.Node, int) {
+			return alg.DFSWithMonitor(elements, target, 1, false, monitor)
 		}
-	}
-	frontIndex := 0
-	backIndex := len(path) - 1
-	addFromFront := true
-
-	for len(addedNodes) < len(path) {
-		var node model.Node
-
-		if addFromFront {
-			for frontIndex < len(path) {
-				if !addedNodes[path[frontIndex].Element] {
-					node = path[frontIndex]
-					addedNodes[node.Element] = true
-					frontIndex++
-					break
-				}
-				frontIndex++
-			}
-		} else {
-			for backIndex >= 0 {
-				if !addedNodes[path[backIndex].Element] {
-					node = path[backIndex]
-					addedNodes[node.Element] = true
-					backIndex--
-					break
-				}
-				backIndex--
-			}
+	case "bidirectional":
+		return func(elements map[string]model.Element, target string, monitor *alg.Monitor) ([][]model.Node, int) {
+			return alg.BidirectionalBFSWithMonitor(elements, target, 1, true, monitor)
 		}
+	}
+	return nil
+}
 
-		addFromFront = !addFromFront
-		if node.Element != "" {
-			isBase := false
-			for _, base := range baseElements {
-				if node.Element == base {
-					isBase = true
-					break
-				}
-			}
+func (h *Handler) animationEventStep(event alg.SearchEvent, stepIndex, totalSteps int) AnimationStep {
+	baseElements := []string{"Water", "Fire", "Earth", "Air"}
 
-			steps = append(steps, map[string]interface{}{
-				"type": "node",
-				"node": map[string]interface{}{
-					"name":      node.Element,
-					"imagePath": node.ImagePath,
-				},
-				"isBaseNode":  isBase,
-				"isCompleted": false,
-			})
-		}
+	step := AnimationStep{
+		Type:       messageEvent,
+		StepIndex:  stepIndex,
+		TotalSteps: totalSteps,
+		Event:      event,
+		Node: AnimationNode{
+			Name:      event.Element,
+			ImagePath: h.elements[event.Element].ImagePath,
+		},
+		IsBaseNode:  utils.IsBaseElementName(event.Element, baseElements),
+		IsCompleted: event.Kind == alg.EventPathFound,
 	}
-
-	for i := 0; i < len(path)-1; i++ {
-		for j := i + 1; j < len(path); j++ {
-			if j == i+1 || (path[j].Ingredients != nil && containsElement(path[j].Ingredients, path[i].Element)) {
-				steps = append(steps, map[string]interface{}{
-					"type": "link",
-					"link": map[string]interface{}{
-						"source": path[i].Element,
-						"target": path[j].Element,
-					},
-					"isBaseNode":  false,
-					"isCompleted": false,
-				})
-			}
-		}
+	if event.Parent != "" {
+		step.Link = &AnimationLink{Source: event.Parent, Target: event.Element}
 	}
 
-	return steps
-}
-
-func containsElement(slice []string, element string) bool {
-	for _, item := range slice {
-		if item == element {
-			return true
-		}
-	}
-	return false
+	return step
 }
@@ -0,0 +1,207 @@
+package main
+
+import (
+	alg "backend/internal/algorithm"
+	"backend/internal/bench"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+func runBench(args []string) int {
+	fs := flag.NewFlagSet("bench", flag.ContinueOnError)
+	dataPath := fs.String("data", "elements.json", "path ke dataset elemen")
+	algorithms := fs.String("algo", "all", "algoritma yang diukur, dipisah koma")
+	all := fs.Bool("all", false, "ukur semua elemen, bukan sampel per tier")
+	perTier := fs.Int("per-tier", 2, "jumlah elemen per tier untuk sampel")
+	elementList := fs.String("elements", "", "daftar elemen yang diukur, dipisah koma (menimpa -all dan -per-tier)")
+	maxResults := fs.Int("max-results", 20, "maxResults untuk setiap pencarian")
+	single := fs.Bool("single", false, "mode single path")
+	timeout := fs.Duration("timeout", 10*time.Second, "batas waktu satu pencarian")
+	repeat := fs.Int("repeat", 1, "pengulangan per pasangan, waktu tercepat yang dicatat")
+	format := fs.String("format", "table", "format output: table, csv atau json")
+	outPath := fs.String("out", "", "tulis output ke file, bukan stdout")
+	baselinePath := fs.String("baseline", "", "report JSON untuk dibandingkan")
+	savePath := fs.String("save", "", "simpan report JSON, bisa dipakai sebagai -baseline berikutnya")
+	threshold := fs.Float64("threshold", 0.10, "perubahan waktu/alokasi yang dianggap regresi (0.1 = 10%)")
+	quiet := fs.Bool("quiet", false, "jangan cetak progress ke stderr")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: alchemy bench [flags]")
+		fs.PrintDefaults()
+	}
+
+	if _, err := parseArgs(fs, args); err != nil {
+		return exitError
+	}
+	if *format != "table" && *format != "csv" && *format != "json" {
+		fmt.Fprintf(os.Stderr, "unknown format %q, expected table, csv or json\n", *format)
+		return exitError
+	}
+
+	e, err := loadEngine(*dataPath)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitError
+	}
+
+	config := bench.Config{
+		Algorithms: splitList(*algorithms),
+		MaxResults: *maxResults,
+		SinglePath: *single,
+		Timeout:    *timeout,
+		Repeat:     *repeat,
+	}
+	if *algorithms == "all" {
+		config.Algorithms = nil
+		for _, algorithm := range alg.Algorithms() {
+			config.Algorithms = append(config.Algorithms, algorithm.Name)
+		}
+	}
+	switch {
+	case *elementList != "":
+		for _, name := range splitList(*elementList) {
+			element, err := e.resolve(name)
+			if err != nil {
+				return exitCodeFor(err)
+			}
+			config.Targets = append(config.Targets, element)
+		}
+	case *all:
+		config.Targets = bench.Targets(e.dataset.Elements)
+	default:
+		config.Targets = bench.SampleByTier(e.dataset.Elements, *perTier)
+	}
+
+	var baseline *bench.Report
+	if *baselinePath != "" {
+		baseline, err = bench.LoadReport(*baselinePath)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return exitError
+		}
+	}
+
+	results, err := bench.Run(e.dataset.Elements, config, func(done, total int, result bench.Result) {
+		if !*quiet {
+			fmt.Fprintf(os.Stderr, "[%d/%d] %s %s %s\n", done, total, result.Algorithm, result.Element, formatNs(result.TimeNs))
+		}
+	})
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitError
+	}
+	report := bench.NewReport(e.dataset.Version, config, results)
+
+	if *savePath != "" {
+		if err := writeFile(*savePath, func(w io.Writer) error { return bench.WriteJSON(w, report) }); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return exitError
+		}
+	}
+
+	var comparisons []bench.Comparison
+	if baseline != nil {
+		comparisons = bench.Compare(baseline, report, *threshold)
+	}
+
+	write := func(w io.Writer) error {
+		switch *format {
+		case "csv":
+			return bench.WriteCSV(w, results)
+		case "json":
+			return bench.WriteJSON(w, report)
+		}
+		writeSummaries(w, report.Summaries)
+		if baseline != nil {
+			fmt.Fprintln(w)
+			writeComparisons(w, baseline, report, comparisons)
+		}
+		return nil
+	}
+	if *outPath != "" {
+		err = writeFile(*outPath, write)
+	} else {
+		err = write(os.Stdout)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitError
+	}
+
+	// perbandingan tetap dicetak kalo outputnya csv/json
+	if baseline != nil && (*format != "table" || *outPath != "") {
+		writeComparisons(os.Stderr, baseline, report, comparisons)
+	}
+	for _, comparison := range comparisons {
+		if comparison.Regression {
+			return exitRegression
+		}
+	}
+	return exitOK
+}
+
+func writeSummaries(w io.Writer, summaries []bench.Summary) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "ALGORITHM\tRUNS\tFOUND\tTIMEOUT\tMEDIAN\tP95\tTOTAL\tALLOCS/RUN\tVISITED/RUN\tSTEPS")
+	for _, s := range summaries {
+		fmt.Fprintf(tw, "%s\t%d\t%d\t%d\t%s\t%s\t%s\t%.0f\t%.0f\t%.2f\n",
+			s.Algorithm, s.Runs, s.Found, s.TimedOut,
+			formatNs(s.MedianTimeNs), formatNs(s.P95TimeNs), formatNs(s.TotalTimeNs),
+			s.MeanAllocs, s.MeanVisited, s.MeanBestSteps)
+	}
+	tw.Flush()
+}
+
+func writeComparisons(w io.Writer, baseline, current *bench.Report, comparisons []bench.Comparison) {
+	if baseline.DatasetVersion != current.DatasetVersion {
+		fmt.Fprintf(w, "warning: baseline uses dataset %s, current dataset is %s\n", baseline.DatasetVersion, current.DatasetVersion)
+	}
+	if len(comparisons) == 0 {
+		fmt.Fprintln(w, "no algorithm/element pairs in common with the baseline")
+		return
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "ALGORITHM\tPAIRS\tTIME\tALLOCS\tFOUND\tSTATUS")
+	for _, c := range comparisons {
+		status := "ok"
+		if c.Regression {
+			status = "REGRESSION: " + strings.Join(c.Reasons, ", ")
+		}
+		fmt.Fprintf(tw, "%s\t%d\t%s → %s (%+.1f%%)\t%+.1f%%\t%d → %d\t%s\n",
+			c.Algorithm, c.Pairs,
+			formatNs(c.BaselineTimeNs), formatNs(c.CurrentTimeNs), c.TimeChange*100,
+			c.AllocChange*100, c.BaselineFound, c.CurrentFound, status)
+	}
+	tw.Flush()
+}
+
+func formatNs(ns int64) string {
+	return time.Duration(ns).Round(time.Microsecond).String()
+}
+
+func splitList(list string) []string {
+	items := make([]string, 0)
+	for _, item := range strings.Split(list, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+func writeFile(path string, write func(w io.Writer) error) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := write(file); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
@@ -0,0 +1,140 @@
+package main
+
+import (
+	"backend/internal/differential"
+	"backend/internal/synthetic"
+	"backend/model"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+func runDiff(args []string) int {
+	fs := flag.NewFlagSet("diff", flag.ContinueOnError)
+	dataPath := fs.String("data", "elements.json", "path ke dataset elemen")
+	syntheticSize := fs.Int("synthetic", 0, "pakai graf sintetis dengan jumlah elemen ini, bukan dataset")
+	seed := fs.Int64("seed", 1, "seed graf sintetis")
+	cycles := fs.Float64("cycles", 0, "peluang resep siklus di graf sintetis (0..1)")
+	algorithms := fs.String("algo", strings.Join(differential.DefaultAlgorithms, ","), "algoritma yang dibandingkan, dipisah koma")
+	elementList := fs.String("elements", "", "daftar elemen yang dicek, dipisah koma (default semua)")
+	maxResults := fs.Int("max-results", 20, "maxResults untuk setiap pencarian")
+	timeout := fs.Duration("timeout", 10*time.Second, "batas waktu satu pencarian, hasil yang timeout tidak dibandingkan")
+	concurrency := fs.Int("concurrency", runtime.NumCPU(), "jumlah elemen yang dicek bersamaan")
+	format := fs.String("format", "table", "format output: table atau json")
+	quiet := fs.Bool("quiet", false, "jangan cetak progress ke stderr")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: alchemy diff [flags]")
+		fs.PrintDefaults()
+	}
+
+	if _, err := parseArgs(fs, args); err != nil {
+		return exitError
+	}
+	if *format != "table" && *format != "json" {
+		fmt.Fprintf(os.Stderr, "unknown format %q, expected table or json\n", *format)
+		return exitError
+	}
+
+	var elements map[string]model.Element
+	config := differential.Config{
+		Algorithms:  splitList(*algorithms),
+		MaxResults:  *maxResults,
+		Timeout:     *timeout,
+		Concurrency: *concurrency,
+	}
+	if *syntheticSize > 0 {
+		synthConfig := synthetic.DefaultConfig()
+		synthConfig.Elements = *syntheticSize
+		synthConfig.Seed = *seed
+		synthConfig.Cycles = *cycles
+		generated, err := synthetic.Generate(synthConfig)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return exitError
+		}
+		elements = synthetic.Map(generated)
+		config.Targets = splitList(*elementList)
+	} else {
+		e, err := loadEngine(*dataPath)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return exitError
+		}
+		elements = e.dataset.Elements
+		for _, name := range splitList(*elementList) {
+			element, err := e.resolve(name)
+			if err != nil {
+				return exitCodeFor(err)
+			}
+			config.Targets = append(config.Targets, element)
+		}
+	}
+
+	report, err := differential.Run(elements, config, func(done, total int, element string) {
+		if !*quiet {
+			fmt.Fprintf(os.Stderr, "\r[%d/%d] %-40s", done, total, element)
+		}
+	})
+	if !*quiet {
+		fmt.Fprintln(os.Stderr)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitError
+	}
+
+	if *format == "json" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(report); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return exitError
+		}
+	} else {
+		writeDisagreements(os.Stdout, report)
+	}
+
+	if len(report.Disagreements) > 0 {
+		return exitDisagreement
+	}
+	return exitOK
+}
+
+func writeDisagreements(w io.Writer, report *differential.Report) {
+	if len(report.Disagreements) > 0 {
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "ELEMENT\tKIND\tEXPECTED\tDETAIL")
+		for _, d := range report.Disagreements {
+			expected := "unreachable"
+			if d.Reachable {
+				expected = fmt.Sprintf("depth %d", d.Depth)
+			}
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", d.Element, d.Kind, expected, d.Detail)
+		}
+		tw.Flush()
+		fmt.Fprintln(w)
+	}
+
+	counts := report.Counts()
+	kinds := make([]string, 0, len(counts))
+	for kind, count := range counts {
+		kinds = append(kinds, fmt.Sprintf("%d %s", count, kind))
+	}
+	sort.Strings(kinds)
+	summary := "all algorithms agree"
+	if len(kinds) > 0 {
+		summary = strings.Join(kinds, ", ")
+	}
+	fmt.Fprintf(w, "%d elements, %s (%s)", report.Elements, summary, strings.Join(report.Algorithms, ", "))
+	if report.TimedOut > 0 {
+		fmt.Fprintf(w, ", %d searches timed out", report.TimedOut)
+	}
+	fmt.Fprintln(w)
+}
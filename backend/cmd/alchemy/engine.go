@@ -0,0 +1,155 @@
+package main
+
+import (
+	"backend/internal"
+	alg "backend/internal/algorithm"
+	"backend/utils"
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// path yang diminta ke algoritma untuk setiap pohon, sama dengan handler
+// tree di API karena banyak path menghasilkan pohon yang sama
+const pathsPerTree = 20
+
+var baseElements = []string{"Water", "Fire", "Earth", "Air"}
+
+// engine menyimpan dataset yang sudah di-load supaya bisa dipakai berkali-kali
+type engine struct {
+	dataset   *internal.Dataset
+	names     []string
+	reachable map[string]bool
+}
+
+func loadEngine(path string) (*engine, error) {
+	policy, err := utils.PolicyFromEnv()
+	if err != nil {
+		return nil, err
+	}
+
+	dataset, err := internal.LoadDataset(path, policy)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load %s: %w", path, err)
+	}
+
+	names := make([]string, 0, len(dataset.Elements))
+	for name := range dataset.Elements {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return &engine{
+		dataset:   dataset,
+		names:     names,
+		reachable: dataset.Graph.ReachableFrom(dataset.Graph.BaseElements),
+	}, nil
+}
+
+type notFoundError struct {
+	element     string
+	suggestions []string
+}
+
+func (e *notFoundError) Error() string {
+	return fmt.Sprintf("element %q not found", e.element)
+}
+
+type unreachableError struct {
+	element string
+	reason  string
+}
+
+func (e *unreachableError) Error() string {
+	if e.reason == "" {
+		return fmt.Sprintf("no recipe found for %q", e.element)
+	}
+	return fmt.Sprintf("no recipe found for %q: %s", e.element, e.reason)
+}
+
+// resolve mencari nama elemen di dataset. Kalo tidak ada yang sama persis,
+// nama dengan huruf besar/kecil berbeda juga diterima supaya enak diketik.
+func (e *engine) resolve(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if _, exists := e.dataset.Elements[name]; exists {
+		return name, nil
+	}
+	for _, candidate := range e.names {
+		if strings.EqualFold(candidate, name) {
+			return candidate, nil
+		}
+	}
+
+	err := &notFoundError{element: name}
+	for _, match := range utils.RankNames(name, e.names, 5) {
+		err.suggestions = append(err.suggestions, match.Name)
+	}
+	return "", err
+}
+
+type searchOptions struct {
+	algorithm  string
+	count      int
+	singlePath bool
+	timeout    time.Duration
+}
+
+// searchResult bentuknya sama dengan response endpoint tree di API
+type searchResult struct {
+	Element      string                   `json:"element"`
+	Algorithm    string                   `json:"algorithm"`
+	IsBase       bool                     `json:"isBaseElement"`
+	Trees        []map[string]interface{} `json:"trees"`
+	NodesVisited int                      `json:"nodesVisited"`
+	TimeElapsed  int64                    `json:"timeElapsed"`
+}
+
+// search mencari sampai opts.count pohon resep untuk elemen yang sudah di-resolve
+func (e *engine) search(element string, opts searchOptions) (*searchResult, error) {
+	algorithm, exists := alg.LookupAlgorithm(opts.algorithm)
+	if !exists {
+		return nil, fmt.Errorf("unknown algorithm %q, run \"alchemy algorithms\" for the list", opts.algorithm)
+	}
+	if opts.count < 1 {
+		return nil, fmt.Errorf("count must be at least 1")
+	}
+
+	result := &searchResult{
+		Element:   element,
+		Algorithm: algorithm.Name,
+		IsBase:    utils.IsBaseElementName(element, baseElements),
+		Trees:     []map[string]interface{}{},
+	}
+	if result.IsBase {
+		return result, nil
+	}
+
+	if !e.reachable[element] {
+		explanation := alg.ExplainElement(e.dataset.Graph, element, baseElements, e.reachable, e.dataset.Report)
+		return nil, &unreachableError{element: element, reason: explanation.Reason}
+	}
+
+	ctx := context.Background()
+	if opts.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.timeout)
+		defer cancel()
+	}
+	monitor := alg.NewMonitor(ctx, nil)
+
+	startTime := time.Now()
+	paths, visited := algorithm.SearchWithMonitor(e.dataset.Elements, element, opts.count*pathsPerTree, opts.singlePath, monitor)
+	result.TimeElapsed = time.Since(startTime).Milliseconds()
+	result.NodesVisited = visited
+	result.Trees = utils.BuildTrees(e.dataset.Elements, paths, element, opts.count)
+
+	if len(result.Trees) == 0 {
+		if ctx.Err() != nil {
+			return nil, fmt.Errorf("search for %q timed out after %s", element, opts.timeout)
+		}
+		return nil, &unreachableError{element: element, reason: algorithm.Name + " did not find a complete recipe"}
+	}
+	return result, nil
+}
@@ -0,0 +1,127 @@
+// Command alchemy menjalankan algoritma pencarian resep langsung dari terminal,
+// tanpa server HTTP.
+//
+//	go run ./cmd/alchemy search Robot --algo bidirectional --count 3 --format tree
+//	go run ./cmd/alchemy search Brick --format dot | dot -Tpng -o brick.png
+//	go run ./cmd/alchemy algorithms
+//	go run ./cmd/alchemy repl
+//	go run ./cmd/alchemy bench -save baseline.json
+//	go run ./cmd/alchemy bench -baseline baseline.json -format csv > bench.csv
+//	go run ./cmd/alchemy generate -elements 100000 -seed 7 -out big.json
+//	go run ./cmd/alchemy bench -data big.json -per-tier 1
+//	go run ./cmd/alchemy diff -algo bfs,dfs,multithreaded-bidirectional
+//	go run ./cmd/alchemy diff -synthetic 200 -seed 3
+//
+// Exit code: 0 berhasil, 1 error lain (argumen salah, dataset gagal dibaca,
+// timeout), 2 elemen tidak ada di dataset, 3 elemen tidak bisa dibuat dari
+// elemen dasar atau algoritma tidak menemukan resep, 4 bench lebih buruk dari
+// baseline, 5 diff menemukan algoritma yang tidak sepakat.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+)
+
+const (
+	exitOK          = 0
+	exitError       = 1
+	exitNotFound    = 2
+	exitUnreachable = 3
+	exitRegression  = 4
+	// diff menemukan elemen di mana algoritma tidak sepakat
+	exitDisagreement = 5
+)
+
+// command adalah satu subcommand, run mengembalikan exit code
+type command struct {
+	name    string
+	summary string
+	run     func(args []string) int
+}
+
+func commands() []command {
+	return []command{
+		{"search", "cari pohon resep satu elemen", runSearch},
+		{"algorithms", "daftar algoritma yang bisa dipakai di --algo", runAlgorithms},
+		{"repl", "shell interaktif untuk menjelajah graf resep", runRepl},
+		{"bench", "ukur semua algoritma dan bandingkan dengan baseline", runBench},
+		{"generate", "buat graf resep sintetis dengan format elements.json", runGenerate},
+		{"diff", "bandingkan hasil beberapa algoritma dan laporkan yang tidak sepakat", runDiff},
+	}
+}
+
+func main() {
+	// log DEBUG dari algoritma terlalu ramai untuk terminal
+	log.SetOutput(io.Discard)
+
+	if len(os.Args) < 2 || os.Args[1] == "-h" || os.Args[1] == "--help" || os.Args[1] == "help" {
+		usage(os.Stderr)
+		if len(os.Args) < 2 {
+			os.Exit(exitError)
+		}
+		return
+	}
+
+	for _, cmd := range commands() {
+		if cmd.name == os.Args[1] {
+			os.Exit(cmd.run(os.Args[2:]))
+		}
+	}
+
+	fmt.Fprintf(os.Stderr, "unknown command %q\n\n", os.Args[1])
+	usage(os.Stderr)
+	os.Exit(exitError)
+}
+
+func usage(w io.Writer) {
+	fmt.Fprintln(w, "usage: alchemy <command> [arguments]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "commands:")
+	for _, cmd := range commands() {
+		fmt.Fprintf(w, "  %-12s %s\n", cmd.name, cmd.summary)
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "jalankan \"alchemy <command> -h\" untuk flag tiap command")
+}
+
+// parseArgs mem-parse flag yang boleh ada sebelum atau sesudah argumen biasa,
+// jadi "search Robot --count 3" dan "search --count 3 Robot" sama saja.
+// Package flag berhenti di argumen non-flag pertama, jadi parse diulang.
+func parseArgs(fs *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+		if fs.NArg() == 0 {
+			return positional, nil
+		}
+		positional = append(positional, fs.Arg(0))
+		args = fs.Args()[1:]
+	}
+}
+
+// exitCodeFor memetakan error pencarian ke exit code dan mencetak pesannya
+func exitCodeFor(err error) int {
+	fmt.Fprintln(os.Stderr, err)
+
+	var notFound *notFoundError
+	var unreachable *unreachableError
+	switch {
+	case errors.As(err, &notFound):
+		if len(notFound.suggestions) > 0 {
+			fmt.Fprintf(os.Stderr, "did you mean: %s\n", strings.Join(notFound.suggestions, ", "))
+		}
+		return exitNotFound
+	case errors.As(err, &unreachable):
+		return exitUnreachable
+	default:
+		return exitError
+	}
+}
@@ -0,0 +1,187 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+type renderer func(w io.Writer, result *searchResult) error
+
+var formats = map[string]renderer{
+	"tree":  renderTree,
+	"steps": renderSteps,
+	"json":  renderJSON,
+	"dot":   renderDot,
+}
+
+func formatNames() []string {
+	names := make([]string, 0, len(formats))
+	for name := range formats {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// helper untuk membaca pohon resep dari utils.BuildTrees
+func nodeName(node map[string]interface{}) string {
+	name, _ := node["name"].(string)
+	return name
+}
+
+func nodeIngredients(node map[string]interface{}) []map[string]interface{} {
+	raw, _ := node["ingredients"].([]interface{})
+	ingredients := make([]map[string]interface{}, 0, len(raw))
+	for _, ing := range raw {
+		if child, ok := ing.(map[string]interface{}); ok {
+			ingredients = append(ingredients, child)
+		}
+	}
+	return ingredients
+}
+
+func nodeFlag(node map[string]interface{}, key string) bool {
+	value, _ := node[key].(bool)
+	return value
+}
+
+// nodeLabel menambahkan keterangan untuk node yang tidak bisa diurai lagi
+func nodeLabel(node map[string]interface{}) string {
+	switch {
+	case nodeFlag(node, "isCircularReference"):
+		return nodeName(node) + " (circular)"
+	case nodeFlag(node, "unmakeable"):
+		return nodeName(node) + " (unmakeable)"
+	}
+	return nodeName(node)
+}
+
+func recipeHeader(w io.Writer, result *searchResult, index int) {
+	if len(result.Trees) > 1 {
+		if index > 0 {
+			fmt.Fprintln(w)
+		}
+		fmt.Fprintf(w, "# Recipe %d of %d\n", index+1, len(result.Trees))
+	}
+}
+
+// renderTree mencetak pohon resep dengan garis ASCII:
+//
+//	Brick
+//	├── Mud
+//	│   ├── Water
+//	│   └── Earth
+//	└── Fire
+func renderTree(w io.Writer, result *searchResult) error {
+	if result.IsBase {
+		fmt.Fprintf(w, "%s (base element)\n", result.Element)
+		return nil
+	}
+
+	for i, tree := range result.Trees {
+		recipeHeader(w, result, i)
+		fmt.Fprintln(w, nodeLabel(tree))
+		writeBranches(w, tree, "")
+	}
+	return nil
+}
+
+func writeBranches(w io.Writer, node map[string]interface{}, prefix string) {
+	ingredients := nodeIngredients(node)
+	for i, child := range ingredients {
+		connector, indent := "├── ", "│   "
+		if i == len(ingredients)-1 {
+			connector, indent = "└── ", "    "
+		}
+		fmt.Fprintln(w, prefix+connector+nodeLabel(child))
+		writeBranches(w, child, prefix+indent)
+	}
+}
+
+// renderSteps mencetak urutan kombinasi dari elemen dasar sampai target,
+// elemen yang muncul berkali-kali di pohon cukup dibuat sekali
+func renderSteps(w io.Writer, result *searchResult) error {
+	if result.IsBase {
+		fmt.Fprintf(w, "%s is a base element\n", result.Element)
+		return nil
+	}
+
+	for i, tree := range result.Trees {
+		recipeHeader(w, result, i)
+		made := make(map[string]bool)
+		step := 0
+		var walk func(node map[string]interface{})
+		walk = func(node map[string]interface{}) {
+			name := nodeName(node)
+			ingredients := nodeIngredients(node)
+			if made[name] || len(ingredients) == 0 {
+				return
+			}
+			made[name] = true
+
+			names := make([]string, len(ingredients))
+			for j, child := range ingredients {
+				walk(child)
+				names[j] = nodeName(child)
+			}
+			step++
+			fmt.Fprintf(w, "%d. %s = %s\n", step, name, strings.Join(names, " + "))
+		}
+		walk(tree)
+	}
+	return nil
+}
+
+func renderJSON(w io.Writer, result *searchResult) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(result)
+}
+
+// renderDot menghasilkan graf Graphviz, satu cluster untuk setiap pohon resep.
+// Setiap node pohon jadi node sendiri karena elemen yang sama bisa muncul di
+// beberapa cabang.
+func renderDot(w io.Writer, result *searchResult) error {
+	fmt.Fprintln(w, "digraph recipes {")
+	fmt.Fprintln(w, "  rankdir=BT;")
+	fmt.Fprintln(w, "  node [shape=box, style=rounded];")
+
+	if result.IsBase {
+		fmt.Fprintf(w, "  n0 [label=%q, style=\"rounded,filled\", fillcolor=lightblue];\n", result.Element)
+	}
+
+	for i, tree := range result.Trees {
+		fmt.Fprintf(w, "  subgraph cluster_%d {\n", i)
+		fmt.Fprintf(w, "    label=%q;\n", fmt.Sprintf("Recipe %d", i+1))
+
+		next := 0
+		var walk func(node map[string]interface{}) string
+		walk = func(node map[string]interface{}) string {
+			id := fmt.Sprintf("t%d_n%d", i, next)
+			next++
+
+			attributes := fmt.Sprintf("label=%q", nodeLabel(node))
+			switch {
+			case nodeFlag(node, "isBaseElement"):
+				attributes += ", style=\"rounded,filled\", fillcolor=lightblue"
+			case nodeFlag(node, "isCircularReference"), nodeFlag(node, "unmakeable"):
+				attributes += ", style=\"rounded,dashed\""
+			}
+			fmt.Fprintf(w, "    %s [%s];\n", id, attributes)
+
+			for _, child := range nodeIngredients(node) {
+				fmt.Fprintf(w, "    %s -> %s;\n", walk(child), id)
+			}
+			return id
+		}
+		walk(tree)
+
+		fmt.Fprintln(w, "  }")
+	}
+
+	fmt.Fprintln(w, "}")
+	return nil
+}
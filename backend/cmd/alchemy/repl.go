@@ -0,0 +1,562 @@
+package main
+
+import (
+	alg "backend/internal/algorithm"
+	"backend/internal/analysis"
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"golang.org/x/term"
+)
+
+const replPrompt = "alchemy> "
+
+// repl adalah shell interaktif untuk menjelajah graf resep. Inventory mulai
+// dari elemen dasar dan bertambah lewat "inventory add" atau "combine".
+type repl struct {
+	e         *engine
+	out       io.Writer
+	inventory map[string]bool
+	algorithm string
+	stats     *analysis.Stats
+}
+
+type replCommand struct {
+	usage   string
+	summary string
+	run     func(r *repl, args []string) error
+}
+
+var replCommands map[string]replCommand
+
+func init() {
+	// diisi di init karena "help" juga membaca replCommands
+	replCommands = map[string]replCommand{
+		"make":      {"make <element>", "pohon resep elemen dengan algoritma sekarang", (*repl).cmdMake},
+		"recipes":   {"recipes <element>", "semua resep langsung untuk membuat elemen", (*repl).cmdRecipes},
+		"uses":      {"uses <element>", "elemen yang memakai elemen ini sebagai ingredient", (*repl).cmdUses},
+		"combine":   {"combine <a> <b>", "hasil kombinasi dua elemen, ditambahkan ke inventory kalo keduanya dimiliki", (*repl).cmdCombine},
+		"inventory": {"inventory [add|remove <elements...> | clear | reset]", "lihat atau ubah inventory", (*repl).cmdInventory},
+		"craftable": {"craftable", "elemen baru yang bisa dibuat dengan satu kombinasi dari inventory", (*repl).cmdCraftable},
+		"reachable": {"reachable [element]", "jumlah elemen yang bisa dibuat dari inventory, atau cek satu elemen", (*repl).cmdReachable},
+		"stats":     {"stats", "statistik dataset", (*repl).cmdStats},
+		"algo":      {"algo [name]", "lihat atau ganti algoritma untuk make", (*repl).cmdAlgo},
+		"help":      {"help", "daftar command", (*repl).cmdHelp},
+	}
+}
+
+func runRepl(args []string) int {
+	fs := flag.NewFlagSet("repl", flag.ContinueOnError)
+	dataPath := fs.String("data", "elements.json", "path ke dataset elemen")
+	algorithm := fs.String("algo", "bfs", "algoritma untuk command make")
+	if _, err := parseArgs(fs, args); err != nil {
+		return exitError
+	}
+	if _, exists := alg.LookupAlgorithm(*algorithm); !exists {
+		fmt.Fprintf(os.Stderr, "unknown algorithm %q\n", *algorithm)
+		return exitError
+	}
+
+	e, err := loadEngine(*dataPath)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitError
+	}
+
+	r := &repl{e: e, algorithm: *algorithm}
+	r.resetInventory()
+
+	// input dari pipe dibaca per baris tanpa prompt, enak untuk script
+	fd := int(os.Stdin.Fd())
+	if !term.IsTerminal(fd) {
+		r.out = os.Stdout
+		scanner := bufio.NewScanner(os.Stdin)
+		for scanner.Scan() {
+			if r.execute(scanner.Text()) {
+				break
+			}
+		}
+		return exitOK
+	}
+
+	state, err := term.MakeRaw(fd)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitError
+	}
+	defer term.Restore(fd, state)
+
+	terminal := term.NewTerminal(struct {
+		io.Reader
+		io.Writer
+	}{os.Stdin, os.Stdout}, replPrompt)
+	terminal.AutoCompleteCallback = r.complete
+	r.out = terminal
+
+	fmt.Fprintf(r.out, "%d elements loaded, type \"help\" for commands, Tab completes element names\n", len(e.names))
+	for {
+		line, err := terminal.ReadLine()
+		if err != nil {
+			// io.EOF dari Ctrl-D
+			return exitOK
+		}
+		if r.execute(line) {
+			return exitOK
+		}
+	}
+}
+
+// execute menjalankan satu baris, true kalo user minta keluar
+func (r *repl) execute(line string) bool {
+	args := splitLine(line)
+	if len(args) == 0 {
+		return false
+	}
+	if args[0] == "exit" || args[0] == "quit" {
+		return true
+	}
+
+	cmd, exists := replCommands[args[0]]
+	if !exists {
+		fmt.Fprintf(r.out, "unknown command %q, type \"help\" for commands\n", args[0])
+		return false
+	}
+	if err := cmd.run(r, args[1:]); err != nil {
+		fmt.Fprintf(r.out, "error: %v\n", err)
+		var notFound *notFoundError
+		if errors.As(err, &notFound) && len(notFound.suggestions) > 0 {
+			fmt.Fprintf(r.out, "did you mean: %s\n", strings.Join(notFound.suggestions, ", "))
+		}
+	}
+	return false
+}
+
+// splitLine memisahkan kata, teks dalam tanda kutip dianggap satu kata
+func splitLine(line string) []string {
+	var words []string
+	var current strings.Builder
+	inQuote, hasWord := false, false
+	for _, c := range line {
+		switch {
+		case c == '"':
+			inQuote = !inQuote
+			hasWord = true
+		case (c == ' ' || c == '\t') && !inQuote:
+			if hasWord {
+				words = append(words, current.String())
+				current.Reset()
+				hasWord = false
+			}
+		default:
+			current.WriteRune(c)
+			hasWord = true
+		}
+	}
+	if hasWord {
+		words = append(words, current.String())
+	}
+	return words
+}
+
+// element me-resolve semua argumen sebagai satu nama, jadi "make Big Bang"
+// tidak perlu dikutip
+func (r *repl) element(args []string) (string, error) {
+	if len(args) == 0 {
+		return "", errors.New("missing element name")
+	}
+	return r.e.resolve(strings.Join(args, " "))
+}
+
+// elements me-resolve beberapa nama sekaligus. Nama dengan spasi dicocokkan
+// dengan mengambil kata terbanyak yang masih membentuk nama elemen.
+func (r *repl) elements(args []string) ([]string, error) {
+	var names []string
+	for i := 0; i < len(args); {
+		matched := false
+		for j := len(args); j > i+1; j-- {
+			if name, err := r.e.resolve(strings.Join(args[i:j], " ")); err == nil {
+				names = append(names, name)
+				i = j
+				matched = true
+				break
+			}
+		}
+		if matched {
+			continue
+		}
+
+		name, err := r.e.resolve(args[i])
+		if err != nil {
+			return nil, err
+		}
+		names = append(names, name)
+		i++
+	}
+	return names, nil
+}
+
+func (r *repl) cmdMake(args []string) error {
+	element, err := r.element(args)
+	if err != nil {
+		return err
+	}
+	result, err := r.e.search(element, searchOptions{algorithm: r.algorithm, count: 1})
+	if err != nil {
+		return err
+	}
+	if err := renderTree(r.out, result); err != nil {
+		return err
+	}
+	if !result.IsBase {
+		fmt.Fprintf(r.out, "(%s, %d nodes visited, %d ms)\n", result.Algorithm, result.NodesVisited, result.TimeElapsed)
+	}
+	return nil
+}
+
+func (r *repl) cmdRecipes(args []string) error {
+	element, err := r.element(args)
+	if err != nil {
+		return err
+	}
+
+	recipes := r.e.dataset.Graph.Nodes[element].RecipesToMakeThisElement
+	if len(recipes) == 0 {
+		fmt.Fprintf(r.out, "%s has no recipes\n", element)
+		return nil
+	}
+	for _, recipe := range recipes {
+		fmt.Fprintf(r.out, "%s = %s%s\n", element, strings.Join(recipe.Ingredients, " + "), r.missingNote(recipe.Ingredients))
+	}
+	return nil
+}
+
+func (r *repl) cmdUses(args []string) error {
+	element, err := r.element(args)
+	if err != nil {
+		return err
+	}
+
+	seen := make(map[string]bool)
+	lines := make([]string, 0)
+	for _, recipe := range r.e.dataset.Graph.Nodes[element].RecipesMakingOtherElements {
+		line := fmt.Sprintf("%s = %s", recipe.Result, strings.Join(recipe.Ingredients, " + "))
+		if !seen[line] {
+			seen[line] = true
+			lines = append(lines, line)
+		}
+	}
+	if len(lines) == 0 {
+		fmt.Fprintf(r.out, "%s is not used in any recipe\n", element)
+		return nil
+	}
+	sort.Strings(lines)
+	for _, line := range lines {
+		fmt.Fprintln(r.out, line)
+	}
+	return nil
+}
+
+func (r *repl) cmdCombine(args []string) error {
+	names, err := r.elements(args)
+	if err != nil {
+		return err
+	}
+	if len(names) != 2 {
+		return errors.New("usage: combine <a> <b>")
+	}
+
+	results := r.e.dataset.Graph.GetPossibleCombinations(names[0], names[1])
+	if len(results) == 0 {
+		fmt.Fprintf(r.out, "%s + %s makes nothing\n", names[0], names[1])
+		return nil
+	}
+	results = uniqueSorted(results)
+
+	owned := r.inventory[names[0]] && r.inventory[names[1]]
+	for _, result := range results {
+		note := ""
+		if owned && !r.inventory[result] {
+			r.inventory[result] = true
+			note = " (added to inventory)"
+		}
+		fmt.Fprintf(r.out, "%s + %s = %s%s\n", names[0], names[1], result, note)
+	}
+	return nil
+}
+
+func (r *repl) cmdInventory(args []string) error {
+	if len(args) == 0 {
+		items := r.inventoryList()
+		fmt.Fprintf(r.out, "%d items: %s\n", len(items), strings.Join(items, ", "))
+		return nil
+	}
+
+	switch args[0] {
+	case "add", "remove":
+		names, err := r.elements(args[1:])
+		if err != nil {
+			return err
+		}
+		if len(names) == 0 {
+			return fmt.Errorf("usage: inventory %s <elements...>", args[0])
+		}
+		for _, name := range names {
+			if args[0] == "add" {
+				r.inventory[name] = true
+			} else {
+				delete(r.inventory, name)
+			}
+		}
+		fmt.Fprintf(r.out, "%d items in inventory\n", len(r.inventory))
+	case "clear":
+		r.inventory = make(map[string]bool)
+		fmt.Fprintln(r.out, "inventory cleared")
+	case "reset":
+		r.resetInventory()
+		fmt.Fprintln(r.out, "inventory reset to base elements")
+	default:
+		return fmt.Errorf("unknown inventory command %q, expected add, remove, clear or reset", args[0])
+	}
+	return nil
+}
+
+func (r *repl) cmdCraftable(args []string) error {
+	craftable := make([]string, 0)
+	for name, node := range r.e.dataset.Graph.Nodes {
+		if r.inventory[name] {
+			continue
+		}
+		for _, recipe := range node.RecipesToMakeThisElement {
+			if len(recipe.Ingredients) > 0 && r.missingNote(recipe.Ingredients) == "" {
+				craftable = append(craftable, fmt.Sprintf("%s = %s", name, strings.Join(recipe.Ingredients, " + ")))
+				break
+			}
+		}
+	}
+
+	if len(craftable) == 0 {
+		fmt.Fprintln(r.out, "nothing new can be made from the inventory")
+		return nil
+	}
+	sort.Strings(craftable)
+	for _, line := range craftable {
+		fmt.Fprintln(r.out, line)
+	}
+	return nil
+}
+
+func (r *repl) cmdReachable(args []string) error {
+	reachable := r.e.dataset.Graph.ReachableFrom(r.inventoryList())
+
+	if len(args) == 0 {
+		fmt.Fprintf(r.out, "%d of %d elements reachable from %d inventory items\n", len(reachable), len(r.e.names), len(r.inventory))
+		return nil
+	}
+
+	element, err := r.element(args)
+	if err != nil {
+		return err
+	}
+	if reachable[element] {
+		fmt.Fprintf(r.out, "%s is reachable from the inventory\n", element)
+	} else {
+		fmt.Fprintf(r.out, "%s is not reachable from the inventory\n", element)
+	}
+	return nil
+}
+
+func (r *repl) cmdStats(args []string) error {
+	if r.stats == nil {
+		r.stats = analysis.ComputeStats(r.e.dataset.Elements, r.e.dataset.Graph)
+	}
+	stats := r.stats
+
+	w := tabwriter.NewWriter(r.out, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "elements\t%d\n", stats.TotalElements)
+	fmt.Fprintf(w, "recipes\t%d\n", stats.TotalRecipes)
+	fmt.Fprintf(w, "reachable\t%d\n", stats.ReachableCount)
+	fmt.Fprintf(w, "unreachable\t%d\n", stats.UnreachableCount)
+	fmt.Fprintf(w, "single recipe\t%d\n", stats.SingleRecipeCount)
+	fmt.Fprintf(w, "average depth\t%.2f\n", stats.AverageDepth)
+	fmt.Fprintf(w, "max depth\t%d (%s)\n", stats.MaxDepth, strings.Join(stats.DeepestElements, ", "))
+	if len(stats.MostUsedIngredients) > 0 {
+		top := stats.MostUsedIngredients
+		if len(top) > 5 {
+			top = top[:5]
+		}
+		used := make([]string, len(top))
+		for i, usage := range top {
+			used[i] = fmt.Sprintf("%s (%d)", usage.Name, usage.Uses)
+		}
+		fmt.Fprintf(w, "most used\t%s\n", strings.Join(used, ", "))
+	}
+	return w.Flush()
+}
+
+func (r *repl) cmdAlgo(args []string) error {
+	if len(args) == 0 {
+		fmt.Fprintf(r.out, "current algorithm: %s\n", r.algorithm)
+		for _, algorithm := range alg.Algorithms() {
+			fmt.Fprintf(r.out, "  %s\n", algorithm.Name)
+		}
+		return nil
+	}
+	if _, exists := alg.LookupAlgorithm(args[0]); !exists {
+		return fmt.Errorf("unknown algorithm %q", args[0])
+	}
+	r.algorithm = args[0]
+	fmt.Fprintf(r.out, "algorithm set to %s\n", r.algorithm)
+	return nil
+}
+
+func (r *repl) cmdHelp(args []string) error {
+	names := make([]string, 0, len(replCommands))
+	for name := range replCommands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	w := tabwriter.NewWriter(r.out, 0, 0, 2, ' ', 0)
+	for _, name := range names {
+		fmt.Fprintf(w, "%s\t%s\n", replCommands[name].usage, replCommands[name].summary)
+	}
+	fmt.Fprintf(w, "exit\tkeluar\n")
+	return w.Flush()
+}
+
+func (r *repl) resetInventory() {
+	r.inventory = make(map[string]bool)
+	for _, base := range r.e.dataset.Graph.BaseElements {
+		r.inventory[base] = true
+	}
+}
+
+func (r *repl) inventoryList() []string {
+	items := make([]string, 0, len(r.inventory))
+	for name := range r.inventory {
+		items = append(items, name)
+	}
+	sort.Strings(items)
+	return items
+}
+
+// missingNote menyebutkan ingredient yang belum ada di inventory
+func (r *repl) missingNote(ingredients []string) string {
+	var missing []string
+	for _, ing := range ingredients {
+		if !r.inventory[ing] {
+			missing = append(missing, ing)
+		}
+	}
+	if len(missing) == 0 {
+		return ""
+	}
+	return " (missing " + strings.Join(uniqueSorted(missing), ", ") + ")"
+}
+
+func uniqueSorted(names []string) []string {
+	seen := make(map[string]bool, len(names))
+	unique := make([]string, 0, len(names))
+	for _, name := range names {
+		if !seen[name] {
+			seen[name] = true
+			unique = append(unique, name)
+		}
+	}
+	sort.Strings(unique)
+	return unique
+}
+
+// complete melengkapi nama command di kata pertama dan nama elemen di kata
+// berikutnya. Nama elemen bisa terdiri dari beberapa kata, jadi dicoba dari
+// potongan terpanjang di belakang kursor dulu.
+func (r *repl) complete(line string, pos int, key rune) (string, int, bool) {
+	if key != '\t' {
+		return "", 0, false
+	}
+	prefix, suffix := line[:pos], line[pos:]
+
+	firstSpace := strings.IndexByte(prefix, ' ')
+	if firstSpace < 0 {
+		names := make([]string, 0, len(replCommands)+2)
+		for name := range replCommands {
+			names = append(names, name)
+		}
+		names = append(names, "exit", "quit")
+		return completeFragment(prefix, 0, names, suffix)
+	}
+
+	command := prefix[:firstSpace]
+	rest := prefix[firstSpace+1:]
+	switch {
+	case command == "algo":
+		names := make([]string, 0)
+		for _, algorithm := range alg.Algorithms() {
+			names = append(names, algorithm.Name)
+		}
+		return completeFragment(prefix, firstSpace+1, names, suffix)
+	case command == "inventory" && !strings.Contains(rest, " "):
+		return completeFragment(prefix, firstSpace+1, []string{"add", "remove", "clear", "reset"}, suffix)
+	}
+
+	for start := firstSpace + 1; start <= len(prefix); start++ {
+		if start > firstSpace+1 && prefix[start-1] != ' ' {
+			continue
+		}
+		if newLine, newPos, ok := completeFragment(prefix, start, r.e.names, suffix); ok {
+			return newLine, newPos, true
+		}
+	}
+	return "", 0, false
+}
+
+// completeFragment mengganti prefix[start:] dengan awalan bersama semua
+// kandidat yang cocok (tidak peka huruf besar/kecil)
+func completeFragment(prefix string, start int, candidates []string, suffix string) (string, int, bool) {
+	fragment := strings.TrimPrefix(prefix[start:], "\"")
+	quoted := len(fragment) < len(prefix[start:])
+	lower := strings.ToLower(fragment)
+
+	var matches []string
+	for _, candidate := range candidates {
+		if strings.HasPrefix(strings.ToLower(candidate), lower) {
+			matches = append(matches, candidate)
+		}
+	}
+	if len(matches) == 0 {
+		return "", 0, false
+	}
+
+	common := matches[0]
+	for _, match := range matches[1:] {
+		n := 0
+		for n < len(common) && n < len(match) && strings.EqualFold(common[n:n+1], match[n:n+1]) {
+			n++
+		}
+		common = common[:n]
+	}
+	if len(common) < len(fragment) {
+		return "", 0, false
+	}
+
+	completed := common
+	if len(matches) == 1 {
+		if quoted {
+			completed += "\""
+		}
+		completed += " "
+	}
+	if quoted {
+		completed = "\"" + completed
+	}
+
+	newLine := prefix[:start] + completed
+	return newLine + suffix, len(newLine), true
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"backend/internal/synthetic"
+	"backend/model"
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// graf kecil dengan resep satu ingredient (generator sudah menolak -fan-in 1,
+// tapi dataset dari luar masih bisa punya) di samping resep biasa
+func TestReplCombineWithOneIngredientRecipe(t *testing.T) {
+	elements := []model.Element{
+		{Name: "Water"}, {Name: "Fire"}, {Name: "Earth"}, {Name: "Air"},
+		{Name: "Steam", Tier: 1, Recipes: []model.ElementRecipe{{Ingredients: []string{"Water", "Fire"}}}},
+		{Name: "Heat", Tier: 1, Recipes: []model.ElementRecipe{{Ingredients: []string{"Fire"}}}},
+	}
+	path := filepath.Join(t.TempDir(), "elements.json")
+	file, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := synthetic.Write(file, elements); err != nil {
+		t.Fatal(err)
+	}
+	file.Close()
+
+	t.Setenv("RECIPE_POLICIES", "none")
+	e, err := loadEngine(path)
+	if err != nil {
+		t.Fatalf("loadEngine: %v", err)
+	}
+	if got := len(e.dataset.Graph.Nodes["Heat"].RecipesToMakeThisElement); got != 1 {
+		t.Fatalf("expected the one-ingredient recipe to be loaded, Heat has %d recipes", got)
+	}
+
+	var out bytes.Buffer
+	r := &repl{e: e, out: &out, algorithm: "bfs"}
+	r.resetInventory()
+
+	tests := []struct {
+		line string
+		want string
+	}{
+		{"combine Water Fire", "Water + Fire = Steam (added to inventory)"},
+		{"combine Fire Water", "Fire + Water = Steam"},
+		{"combine Fire Fire", "Fire + Fire makes nothing"},
+		{"combine Fire Earth", "Fire + Earth makes nothing"},
+	}
+	for _, test := range tests {
+		out.Reset()
+		r.execute(test.line)
+		if got := strings.TrimSpace(out.String()); got != test.want {
+			t.Errorf("%q printed %q, expected %q", test.line, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	alg "backend/internal/algorithm"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+func runSearch(args []string) int {
+	fs := flag.NewFlagSet("search", flag.ContinueOnError)
+	dataPath := fs.String("data", "elements.json", "path ke dataset elemen")
+	algorithm := fs.String("algo", "bfs", "algoritma pencarian, lihat \"alchemy algorithms\"")
+	count := fs.Int("count", 1, "jumlah pohon resep yang dicari")
+	single := fs.Bool("single", false, "mode single path seperti ?single=true di API")
+	format := fs.String("format", "tree", "format output: "+strings.Join(formatNames(), ", "))
+	timeout := fs.Duration("timeout", 30*time.Second, "batas waktu pencarian (0 = tanpa batas)")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: alchemy search <element> [flags]")
+		fs.PrintDefaults()
+	}
+
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return exitError
+	}
+	if len(positional) == 0 {
+		fs.Usage()
+		return exitError
+	}
+	render, exists := formats[*format]
+	if !exists {
+		fmt.Fprintf(os.Stderr, "unknown format %q, expected one of: %s\n", *format, strings.Join(formatNames(), ", "))
+		return exitError
+	}
+
+	e, err := loadEngine(*dataPath)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitError
+	}
+
+	// nama dengan spasi boleh tidak dikutip: alchemy search Big Bang
+	element, err := e.resolve(strings.Join(positional, " "))
+	if err != nil {
+		return exitCodeFor(err)
+	}
+
+	result, err := e.search(element, searchOptions{algorithm: *algorithm, count: *count, singlePath: *single, timeout: *timeout})
+	if err != nil {
+		return exitCodeFor(err)
+	}
+
+	if err := render(os.Stdout, result); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitError
+	}
+	return exitOK
+}
+
+func runAlgorithms(args []string) int {
+	fs := flag.NewFlagSet("algorithms", flag.ContinueOnError)
+	if _, err := parseArgs(fs, args); err != nil {
+		return exitError
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	for _, algorithm := range alg.Algorithms() {
+		fmt.Fprintf(w, "%s\t%s\n", algorithm.Name, algorithm.Description)
+	}
+	w.Flush()
+	return exitOK
+}
@@ -12,6 +12,11 @@ import (
 )
 
 func BFS(elements map[string]model.Element, target string, maxResults int, singlePath bool) ([][]model.Node, int) {
+	return BFSWithMonitor(elements, target, maxResults, singlePath, nil)
+}
+
+// BFSWithMonitor sama dengan BFS tapi bisa dihentikan dan dipantau lewat monitor
+func BFSWithMonitor(elements map[string]model.Element, target string, maxResults int, singlePath bool, monitor *Monitor) ([][]model.Node, int) {
 	log.Printf("DEBUG: Starting top-down BFS for target: %s (max results: %d)", target, maxResults)
 
 	g := graph.NewElementGraph(elements)
@@ -41,6 +46,7 @@ func BFS(elements map[string]model.Element, target string, maxResults int, singl
 	type queueItem struct {
 		recipe *graph.Recipe
 		path   []*model.Node
+		depth  int
 	}
 
 	uniquePaths := make(map[string]bool)
@@ -57,11 +63,17 @@ func BFS(elements map[string]model.Element, target string, maxResults int, singl
 		queue := []queueItem{
 			{recipe: recipe, path: startPath},
 		}
+		monitor.Emit(SearchEvent{Kind: EventEnqueued, Element: target, Ingredients: recipe.Ingredients})
 
 		visited := make(map[string]bool)
 		visited[target] = true
 
 		for len(queue) > 0 && (len(completePaths) < maxResults || !singlePath) {
+			if monitor.Cancelled() {
+				log.Printf("DEBUG: BFS for %s cancelled", target)
+				break
+			}
+
 			current := queue[0]
 			queue = queue[1:]
 
@@ -69,6 +81,8 @@ func BFS(elements map[string]model.Element, target string, maxResults int, singl
 			currentPath := current.path
 
 			visitedCount++
+			monitor.Visit(1)
+			monitor.Emit(SearchEvent{Kind: EventExpanded, Element: currentRecipe.Result, Ingredients: currentRecipe.Ingredients, Depth: current.depth})
 
 			allIngredientsAreBaseElements := true
 			hasUnmakeableElement := false
@@ -105,6 +119,7 @@ func BFS(elements map[string]model.Element, target string, maxResults int, singl
 			}
 
 			if singlePath && hasUnmakeableElement {
+				monitor.Emit(SearchEvent{Kind: EventPruned, Element: currentRecipe.Result, Ingredients: currentRecipe.Ingredients, Depth: current.depth, Reason: "unmakeable ingredient"})
 				continue
 			}
 
@@ -129,6 +144,8 @@ func BFS(elements map[string]model.Element, target string, maxResults int, singl
 					if !hasUnmakeableElement {
 						completePaths = append(completePaths, finalPath)
 						log.Printf("DEBUG: Found complete path with %d steps", len(finalPath))
+						monitor.PathFound(finalPath)
+						monitor.Emit(SearchEvent{Kind: EventPathFound, Element: target, Depth: current.depth})
 
 						if singlePath {
 							return [][]model.Node{finalPath}, visitedCount
@@ -151,7 +168,11 @@ func BFS(elements map[string]model.Element, target string, maxResults int, singl
 					}
 				}
 
-				if isBase || visited[ingredient] {
+				if isBase {
+					continue
+				}
+				if visited[ingredient] {
+					monitor.Emit(SearchEvent{Kind: EventPruned, Element: ingredient, Parent: currentRecipe.Result, Depth: current.depth + 1, Reason: "cycle"})
 					continue
 				}
 
@@ -173,7 +194,9 @@ func BFS(elements map[string]model.Element, target string, maxResults int, singl
 					queue = append(queue, queueItem{
 						recipe: subRecipe,
 						path:   ingredientPath,
+						depth:  current.depth + 1,
 					})
+					monitor.Emit(SearchEvent{Kind: EventEnqueued, Element: ingredient, Parent: currentRecipe.Result, Ingredients: subRecipe.Ingredients, Depth: current.depth + 1})
 				}
 
 				delete(visited, ingredient)
@@ -223,6 +246,12 @@ func BFS(elements map[string]model.Element, target string, maxResults int, singl
 }
 
 func MultiThreadedBFS(elements map[string]model.Element, target string, maxResults int, singlePath bool) ([][]model.Node, int) {
+	return MultiThreadedBFSWithMonitor(elements, target, maxResults, singlePath, nil)
+}
+
+// MultiThreadedBFSWithMonitor sama dengan MultiThreadedBFS tapi bisa dihentikan
+// dan dipantau lewat monitor
+func MultiThreadedBFSWithMonitor(elements map[string]model.Element, target string, maxResults int, singlePath bool, monitor *Monitor) ([][]model.Node, int) {
 	g := graph.NewElementGraph(elements)
 	targetNode, ok := g.Nodes[target]
 	if !ok {
@@ -297,10 +326,14 @@ func MultiThreadedBFS(elements map[string]model.Element, target string, maxResul
 					return
 				default:
 				}
+				if monitor.Cancelled() {
+					break
+				}
 
 				item := queue[0]
 				queue = queue[1:]
 				localVisited++
+				monitor.Visit(1)
 
 				allBase := true
 				hasDeadEnd := false
@@ -344,7 +377,7 @@ func MultiThreadedBFS(elements map[string]model.Element, target string, maxResul
 					continue
 				}
 
-				newPath = append(newPath, nextNodes[i])
+				newPath = append(newPath, nextNodes...)
 
 				if allBase || (hasDeadEnd && !singlePath) {
 
@@ -356,13 +389,26 @@ func MultiThreadedBFS(elements map[string]model.Element, target string, maxResul
 					mu.Lock()
 					log.Printf("DEBUG: Found complete path in goroutine %d: %s", recipeIdx, pathToString(reversedPath))
 
+					// kirim sambil dengar stopChan, kalo collector sudah berhenti
+					// baca channel yang penuh tidak boleh bikin goroutine macet
 					if allBase && !hasDeadEnd {
-						completePathChan <- reversedPath
+						select {
+						case completePathChan <- reversedPath:
+						case <-stopChan:
+						}
+						monitor.PathFound(reversedPath)
 						if singlePath {
-							close(stopChan)
+							select {
+							case <-stopChan:
+							default:
+								close(stopChan)
+							}
 						}
 					} else if !singlePath {
-						resultChan <- reversedPath
+						select {
+						case resultChan <- reversedPath:
+						case <-stopChan:
+						}
 					}
 					mu.Unlock()
 					continue
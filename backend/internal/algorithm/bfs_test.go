@@ -0,0 +1,54 @@
+package algorithm_test
+
+import (
+	alg "backend/internal/algorithm"
+	"backend/model"
+	"io"
+	"log"
+	"os"
+	"testing"
+)
+
+// TestMultiThreadedBFSPathHasAllIngredients memastikan path dari goroutine
+// setiap resep berisi semua ingredient resep itu. Dulu path diisi
+// nextNodes[i] dengan i indeks resep, jadi cuma satu ingredient yang masuk
+// dan resep ketiga ke atas panic karena index out of range.
+func TestMultiThreadedBFSPathHasAllIngredients(t *testing.T) {
+	log.SetOutput(io.Discard)
+	defer log.SetOutput(os.Stderr)
+
+	recipes := [][]string{{"Water", "Fire"}, {"Earth", "Air"}, {"Water", "Earth"}, {"Fire", "Air"}}
+	elements := map[string]model.Element{
+		"Water": {Name: "Water"},
+		"Fire":  {Name: "Fire"},
+		"Earth": {Name: "Earth"},
+		"Air":   {Name: "Air"},
+		"Mix":   {Name: "Mix", Tier: 1},
+	}
+	mix := elements["Mix"]
+	for _, ingredients := range recipes {
+		mix.Recipes = append(mix.Recipes, model.ElementRecipe{Ingredients: ingredients})
+	}
+	elements["Mix"] = mix
+
+	paths, _ := alg.MultiThreadedBFS(elements, "Mix", 10, false)
+	if len(paths) != len(recipes) {
+		t.Fatalf("expected one path per recipe (%d), got %d", len(recipes), len(paths))
+	}
+
+	for _, path := range paths {
+		last := path[len(path)-1]
+		if last.Element != "Mix" {
+			t.Fatalf("path %v does not end at Mix", path)
+		}
+		seen := make(map[string]bool)
+		for _, node := range path[:len(path)-1] {
+			seen[node.Element] = true
+		}
+		for _, ingredient := range last.Ingredients {
+			if !seen[ingredient] {
+				t.Errorf("path %v is missing ingredient %s of recipe %v", path, ingredient, last.Ingredients)
+			}
+		}
+	}
+}
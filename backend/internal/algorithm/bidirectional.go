@@ -4,10 +4,13 @@ import (
 	"backend/internal/graph"
 	"backend/model"
 	"backend/utils"
+	"context"
+	"fmt"
 	"log"
 	"sort"
 	"strings"
 	"sync"
+	"time"
 )
 
 type PathSegment struct {
@@ -16,6 +19,12 @@ type PathSegment struct {
 }
 
 func BidirectionalBFS(elements map[string]model.Element, target string, maxResults int, singlePath bool) ([][]model.Node, int) {
+	return BidirectionalBFSWithMonitor(elements, target, maxResults, singlePath, nil)
+}
+
+// BidirectionalBFSWithMonitor sama dengan BidirectionalBFS tapi bisa dihentikan
+// dan dipantau lewat monitor
+func BidirectionalBFSWithMonitor(elements map[string]model.Element, target string, maxResults int, singlePath bool, monitor *Monitor) ([][]model.Node, int) {
 	log.Printf("DEBUG: Starting Bidirectional BFS for target: %s (max results: %d)", target, maxResults)
 
 	g := graph.NewElementGraph(elements)
@@ -34,10 +43,16 @@ func BidirectionalBFS(elements map[string]model.Element, target string, maxResul
 		}
 	}
 
+	reachable := g.ReachableFrom(baseElements)
+	if !reachable[target] {
+		log.Printf("DEBUG: Target '%s' cannot be made from base elements", target)
+		return [][]model.Node{}, 0
+	}
+
 	targetRecipes := make(map[string][]string)
 	if targetNode := g.Nodes[target]; targetNode != nil {
 		for _, recipe := range targetNode.RecipesToMakeThisElement {
-			if len(recipe.Ingredients) == 2 {
+			if len(recipe.Ingredients) == 2 && reachable[recipe.Ingredients[0]] && reachable[recipe.Ingredients[1]] {
 				sortedIngs := make([]string, len(recipe.Ingredients))
 				copy(sortedIngs, recipe.Ingredients)
 				sort.Strings(sortedIngs)
@@ -47,6 +62,8 @@ func BidirectionalBFS(elements map[string]model.Element, target string, maxResul
 		}
 	}
 	log.Printf("DEBUG: Found %d valid recipes for target '%s'", len(targetRecipes), target)
+	// map diiterasi lewat key terurut supaya hasil pencarian selalu sama
+	targetRecipeKeys := sortedKeys(targetRecipes)
 
 	forwardFrontier := make([]PathSegment, 0)
 	backwardFrontier := make([]PathSegment, 0)
@@ -79,7 +96,8 @@ func BidirectionalBFS(elements map[string]model.Element, target string, maxResul
 		visitedCount++
 	}
 
-	for _, recipe := range targetRecipes {
+	for _, recipeKey := range targetRecipeKeys {
+		recipe := targetRecipes[recipeKey]
 		imgPath := ""
 		if elemData, exists := elements[target]; exists {
 			imgPath = elemData.ImagePath
@@ -123,6 +141,22 @@ func BidirectionalBFS(elements map[string]model.Element, target string, maxResul
 
 	backwardVisited[target] = backwardFrontier[0].Path
 	visitedCount++
+	monitor.Visit(visitedCount)
+
+	// laporkan jumlah kunjungan dan path baru ke monitor setelah tiap ekspansi
+	reportedVisits := visitedCount
+	reportedResults := 0
+	reportProgress := func() {
+		monitor.Visit(visitedCount - reportedVisits)
+		reportedVisits = visitedCount
+		for _, path := range results[reportedResults:] {
+			if fixedPath := postProcessPath(path, elements, g); validateIngredientsInPath(fixedPath) {
+				monitor.PathFound(fixedPath)
+				monitor.Emit(SearchEvent{Kind: EventPathFound, Element: target, Depth: len(fixedPath) - 1})
+			}
+		}
+		reportedResults = len(results)
+	}
 
 	maxIterations := 50
 	maxIterationsWithoutProgress := 10
@@ -138,6 +172,11 @@ func BidirectionalBFS(elements map[string]model.Element, target string, maxResul
 			break
 		}
 
+		if monitor.Cancelled() {
+			log.Printf("DEBUG: Bidirectional search for %s cancelled", target)
+			break
+		}
+
 		startingRecipeCount := len(recipeResults)
 
 		if len(forwardFrontier) > 0 {
@@ -149,7 +188,9 @@ func BidirectionalBFS(elements map[string]model.Element, target string, maxResul
 				elements,
 				g,
 				&visitedCount,
+				monitor,
 			)
+			reportProgress()
 
 			for _, path := range results[lastResultCount:] {
 				if len(path) > 0 {
@@ -208,7 +249,10 @@ func BidirectionalBFS(elements map[string]model.Element, target string, maxResul
 				g,
 				&visitedCount,
 				baseElements,
+				reachable,
+				monitor,
 			)
+			reportProgress()
 
 			for _, path := range results[lastResultCount:] {
 				if len(path) > 0 {
@@ -261,6 +305,25 @@ func BidirectionalBFS(elements map[string]model.Element, target string, maxResul
 		log.Printf("DEBUG: Recipe '%s': %d paths", recipeKey, len(paths))
 	}
 
+	// completePath butuh path forward untuk setiap ingredient, jadi pencarian
+	// forward diteruskan sampai semua elemen yang bisa dibuat sudah dicapai.
+	// Path pertemuan baru dari sini tidak dipakai.
+	if len(results) > 0 {
+		var discarded [][]model.Node
+		for len(forwardFrontier) > 0 && !monitor.Cancelled() {
+			expandForwardFrontier(&forwardFrontier, forwardVisited, backwardVisited, &discarded, elements, g, &visitedCount, monitor)
+		}
+		monitor.Visit(visitedCount - reportedVisits)
+	}
+	for _, paths := range recipeResults {
+		for i, path := range paths {
+			paths[i] = completePath(path, forwardVisited, baseElements)
+		}
+	}
+	for i, path := range results {
+		results[i] = completePath(path, forwardVisited, baseElements)
+	}
+
 	var validResults [][]model.Node
 	for _, path := range results {
 		fixedPath := postProcessPath(path, elements, g)
@@ -269,10 +332,11 @@ func BidirectionalBFS(elements map[string]model.Element, target string, maxResul
 		}
 	}
 
-	if len(validResults) < maxResults && len(targetRecipes) > 0 {
+	if len(validResults) < maxResults && len(targetRecipes) > 0 && !monitor.Cancelled() {
 		log.Printf("DEBUG: Standard bidirectional search found only %d valid paths, trying targeted approach", len(validResults))
 
-		for _, ingredients := range targetRecipes {
+		for _, key := range targetRecipeKeys {
+			ingredients := targetRecipes[key]
 			recipeKey := getRecipeKey(ingredients)
 			if len(recipeResults[recipeKey]) >= 2 {
 				continue
@@ -291,10 +355,12 @@ func BidirectionalBFS(elements map[string]model.Element, target string, maxResul
 			for _, path := range customResults {
 				if validateIngredientsInPath(path) {
 					validResults = append(validResults, path)
+					monitor.PathFound(path)
 				}
 			}
 
 			visitedCount += customVisited
+			monitor.Visit(customVisited)
 
 			if len(validResults) >= maxResults && !singlePath {
 				break
@@ -304,8 +370,10 @@ func BidirectionalBFS(elements map[string]model.Element, target string, maxResul
 
 	if !singlePath && len(recipeResults) > 1 {
 		var diverseResults [][]model.Node
+		resultKeys := sortedKeys(recipeResults)
 
-		for _, paths := range recipeResults {
+		for _, recipeKey := range resultKeys {
+			paths := recipeResults[recipeKey]
 			if len(paths) > 0 {
 				var bestPath []model.Node
 				for _, path := range paths {
@@ -325,8 +393,8 @@ func BidirectionalBFS(elements map[string]model.Element, target string, maxResul
 		remainingSlots := maxResults - len(diverseResults)
 		if remainingSlots > 0 {
 			var remainingPaths [][]model.Node
-			for _, paths := range recipeResults {
-				for _, path := range paths {
+			for _, recipeKey := range resultKeys {
+				for _, path := range recipeResults[recipeKey] {
 					alreadyIncluded := false
 					for _, included := range diverseResults {
 						if pathsEqual(path, included) {
@@ -341,7 +409,7 @@ func BidirectionalBFS(elements map[string]model.Element, target string, maxResul
 				}
 			}
 
-			sort.Slice(remainingPaths, func(i, j int) bool {
+			sort.SliceStable(remainingPaths, func(i, j int) bool {
 				return len(remainingPaths[i]) < len(remainingPaths[j])
 			})
 
@@ -356,7 +424,7 @@ func BidirectionalBFS(elements map[string]model.Element, target string, maxResul
 			validResults = diverseResults
 		}
 	} else {
-		sort.Slice(validResults, func(i, j int) bool {
+		sort.SliceStable(validResults, func(i, j int) bool {
 			return len(validResults[i]) < len(validResults[j])
 		})
 	}
@@ -371,6 +439,16 @@ func BidirectionalBFS(elements map[string]model.Element, target string, maxResul
 	return validResults, visitedCount
 }
 
+// sortedKeys mengembalikan key map terurut
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
 func getRecipeKey(ingredients []string) string {
 	if len(ingredients) == 0 {
 		return ""
@@ -434,7 +512,7 @@ func postProcessPath(path []model.Node, elements map[string]model.Element, g *gr
 	return result
 }
 
-func expandForwardFrontier(frontier *[]PathSegment, visited map[string][]model.Node, otherVisited map[string][]model.Node, results *[][]model.Node, elements map[string]model.Element, g *graph.ElementGraph, visitedCount *int) int {
+func expandForwardFrontier(frontier *[]PathSegment, visited map[string][]model.Node, otherVisited map[string][]model.Node, results *[][]model.Node, elements map[string]model.Element, g *graph.ElementGraph, visitedCount *int, monitor *Monitor) int {
 	if len(*frontier) == 0 {
 		return 0
 	}
@@ -448,8 +526,13 @@ func expandForwardFrontier(frontier *[]PathSegment, visited map[string][]model.N
 		return len(currentLevel[i].Path) < len(currentLevel[j].Path)
 	})
 
+	// segmen di luar batas sudah masuk visited, jadi harus tetap diekspansi
+	// di iterasi berikutnya. Kalo dibuang, semua elemen yang hanya bisa
+	// dicapai lewat segmen itu tidak pernah ditemukan.
 	maxPaths := 100
+	var deferred []PathSegment
 	if len(currentLevel) > maxPaths {
+		deferred = currentLevel[maxPaths:]
 		currentLevel = currentLevel[:maxPaths]
 	}
 
@@ -461,6 +544,7 @@ func expandForwardFrontier(frontier *[]PathSegment, visited map[string][]model.N
 		if node == nil {
 			continue
 		}
+		monitor.Emit(SearchEvent{Kind: EventExpanded, Element: currentElem, Depth: len(currentPath) - 1, Direction: DirectionForward})
 
 		for _, recipe := range node.RecipesMakingOtherElements {
 			if len(recipe.Ingredients) != 2 {
@@ -507,8 +591,10 @@ func expandForwardFrontier(frontier *[]PathSegment, visited map[string][]model.N
 				Path:     newPath,
 				LastElem: resultElem,
 			})
+			monitor.Emit(SearchEvent{Kind: EventEnqueued, Element: resultElem, Parent: currentElem, Ingredients: recipe.Ingredients, Depth: len(newPath) - 1, Direction: DirectionForward})
 
 			if backwardPath, found := otherVisited[resultElem]; found {
+				monitor.Emit(SearchEvent{Kind: EventFrontierMet, Element: resultElem, Parent: currentElem, Depth: len(newPath) - 1, Direction: DirectionForward})
 				completePath := mergePaths(newPath, backwardPath)
 
 				if validateIngredientsInPath(completePath) {
@@ -520,10 +606,53 @@ func expandForwardFrontier(frontier *[]PathSegment, visited map[string][]model.N
 			}
 		}
 	}
-	*frontier = nextFrontier
+	*frontier = append(deferred, nextFrontier...)
 	return connectionsFound
 }
 
+// completePath menambahkan path forward untuk setiap ingredient yang belum ada
+// di path. Path hasil pertemuan dua arah cuma satu rantai elemen, jadi
+// ingredient kedua dari resep di rantai itu sering tidak ada dan path-nya
+// dibuang validateIngredientsInPath. Ingredient yang belum pernah dicapai
+// pencarian forward dibiarkan hilang.
+func completePath(path []model.Node, forwardVisited map[string][]model.Node, baseElements []string) []model.Node {
+	inPath := make(map[string]bool, len(path))
+	for _, node := range path {
+		inPath[node.Element] = true
+	}
+
+	result := path
+	for changed := true; changed; {
+		changed = false
+		for _, node := range result {
+			for _, ingredient := range node.Ingredients {
+				if inPath[ingredient] || utils.IsBaseElementName(ingredient, baseElements) {
+					continue
+				}
+				ingredientPath, found := forwardVisited[ingredient]
+				if !found {
+					continue
+				}
+
+				var missing []model.Node
+				for _, ingredientNode := range ingredientPath {
+					if !inPath[ingredientNode.Element] {
+						inPath[ingredientNode.Element] = true
+						missing = append(missing, ingredientNode)
+					}
+				}
+				result = append(missing, result...)
+				changed = true
+				break
+			}
+			if changed {
+				break
+			}
+		}
+	}
+	return result
+}
+
 func ensureIngredientInPath(path *[]model.Node, ingredientPath []model.Node, ingredient string) {
 	for _, node := range *path {
 		if node.Element == ingredient {
@@ -544,7 +673,10 @@ func ensureIngredientInPath(path *[]model.Node, ingredientPath []model.Node, ing
 	*path = newPath
 }
 
-func expandBackwardFrontier(frontier *[]PathSegment, visited map[string][]model.Node, otherVisited map[string][]model.Node, results *[][]model.Node, elements map[string]model.Element, g *graph.ElementGraph, visitedCount *int, baseElements []string) int {
+// expandBackwardFrontier mundur satu level dari frontier. Hanya resep yang
+// semua ingredient-nya bisa dibuat yang diikuti, rantai lewat elemen yang
+// tidak bisa dibuat menghasilkan pohon yang tidak valid.
+func expandBackwardFrontier(frontier *[]PathSegment, visited map[string][]model.Node, otherVisited map[string][]model.Node, results *[][]model.Node, elements map[string]model.Element, g *graph.ElementGraph, visitedCount *int, baseElements []string, reachable map[string]bool, monitor *Monitor) int {
 	if len(*frontier) == 0 {
 		return 0
 	}
@@ -562,9 +694,10 @@ func expandBackwardFrontier(frontier *[]PathSegment, visited map[string][]model.
 		if node == nil {
 			continue
 		}
+		monitor.Emit(SearchEvent{Kind: EventExpanded, Element: currentElem, Depth: len(currentPath) - 1, Direction: DirectionBackward})
 
 		for _, recipe := range node.RecipesToMakeThisElement {
-			if len(recipe.Ingredients) != 2 {
+			if len(recipe.Ingredients) != 2 || !reachable[recipe.Ingredients[0]] || !reachable[recipe.Ingredients[1]] {
 				continue
 			}
 
@@ -594,10 +727,12 @@ func expandBackwardFrontier(frontier *[]PathSegment, visited map[string][]model.
 					Path:     newPath,
 					LastElem: ingredient,
 				})
+				monitor.Emit(SearchEvent{Kind: EventEnqueued, Element: ingredient, Parent: currentElem, Ingredients: recipe.Ingredients, Depth: len(newPath) - 1, Direction: DirectionBackward})
 
 				isBase := utils.IsBaseElementName(ingredient, baseElements)
 
 				if isBase || otherVisited[ingredient] != nil {
+					monitor.Emit(SearchEvent{Kind: EventFrontierMet, Element: ingredient, Parent: currentElem, Depth: len(newPath) - 1, Direction: DirectionBackward})
 					forwardPath := otherVisited[ingredient]
 
 					if forwardPath == nil && isBase {
@@ -679,6 +814,12 @@ func pathsEqual(path1, path2 []model.Node) bool {
 }
 
 func MultiThreadedBidirectionalBFS(elements map[string]model.Element, target string, maxResults int, singlePath bool) ([][]model.Node, int) {
+	return MultiThreadedBidirectionalBFSWithMonitor(elements, target, maxResults, singlePath, nil)
+}
+
+// MultiThreadedBidirectionalBFSWithMonitor sama dengan MultiThreadedBidirectionalBFS
+// tapi bisa dihentikan dan dipantau lewat monitor
+func MultiThreadedBidirectionalBFSWithMonitor(elements map[string]model.Element, target string, maxResults int, singlePath bool, monitor *Monitor) ([][]model.Node, int) {
 	log.Printf("DEBUG: Starting Multi-threaded Bidirectional BFS for target: %s", target)
 
 	g := graph.NewElementGraph(elements)
@@ -763,6 +904,10 @@ func MultiThreadedBidirectionalBFS(elements map[string]model.Element, target str
 			defer wg.Done()
 
 			for recipe := range recipeChan {
+				if monitor.Cancelled() {
+					continue
+				}
+
 				imgPathTarget := ""
 				if elemData, exists := elements[target]; exists {
 					imgPathTarget = elemData.ImagePath
@@ -811,6 +956,7 @@ func MultiThreadedBidirectionalBFS(elements map[string]model.Element, target str
 					mu.Lock()
 					totalVisits += visited1
 					mu.Unlock()
+					monitor.Visit(visited1)
 				}
 
 				if ingredient2IsBase {
@@ -841,6 +987,7 @@ func MultiThreadedBidirectionalBFS(elements map[string]model.Element, target str
 					mu.Lock()
 					totalVisits += visited2
 					mu.Unlock()
+					monitor.Visit(visited2)
 				}
 
 				log.Printf("DEBUG: For recipe [%s + %s], found %d and %d paths for ingredients",
@@ -877,6 +1024,7 @@ func MultiThreadedBidirectionalBFS(elements map[string]model.Element, target str
 
 							if validateIngredientsInPath(finalPath) {
 								pathChan <- finalPath
+								monitor.PathFound(finalPath)
 							}
 						}
 					}
@@ -914,7 +1062,7 @@ func MultiThreadedBidirectionalBFS(elements map[string]model.Element, target str
 		allPaths = append(allPaths, path)
 	}
 
-	if len(allPaths) < len(validRecipes) {
+	if len(allPaths) < len(validRecipes) && !monitor.Cancelled() {
 		log.Printf("DEBUG: Multi-threaded approach found paths for only %d/%d recipes, trying standard approach",
 			len(allPaths), len(validRecipes))
 
@@ -939,6 +1087,7 @@ func MultiThreadedBidirectionalBFS(elements map[string]model.Element, target str
 				)
 
 				totalVisits += customVisited
+				monitor.Visit(customVisited)
 
 				if len(customResults) > 0 {
 					sort.Slice(customResults, func(i, j int) bool {
@@ -949,6 +1098,7 @@ func MultiThreadedBidirectionalBFS(elements map[string]model.Element, target str
 						if validateIngredientsInPath(path) {
 							allPaths = append(allPaths, path)
 							foundRecipes[recipeKey] = true
+							monitor.PathFound(path)
 							break
 						}
 					}
@@ -1316,16 +1466,22 @@ func checkForIngredientConnections(forwardVisited map[string][]model.Node, targe
 }
 
 func HybridSearch(elements map[string]model.Element, target string, maxResults int, singlePath bool) ([][]model.Node, int) {
+	return HybridSearchWithMonitor(elements, target, maxResults, singlePath, nil)
+}
+
+// HybridSearchWithMonitor sama dengan HybridSearch tapi bisa dihentikan dan
+// dipantau lewat monitor
+func HybridSearchWithMonitor(elements map[string]model.Element, target string, maxResults int, singlePath bool, monitor *Monitor) ([][]model.Node, int) {
 	log.Printf("DEBUG: Starting hybrid search for target: %s", target)
 
-	paths, visited := MultiThreadedBidirectionalBFS(elements, target, maxResults, singlePath)
+	paths, visited := MultiThreadedBidirectionalBFSWithMonitor(elements, target, maxResults, singlePath, monitor)
 
-	if len(paths) > 0 {
+	if len(paths) > 0 || monitor.Cancelled() {
 		return paths, visited
 	}
 
 	log.Printf("DEBUG: Multi-threaded bidirectional search failed, falling back to standard bidirectional BFS")
-	return BidirectionalBFS(elements, target, maxResults, singlePath)
+	return BidirectionalBFSWithMonitor(elements, target, maxResults, singlePath, monitor)
 }
 
 func ConcurrentElementSearch(elements map[string]model.Element, targets []string, maxResultsPerTarget int, singlePath bool) map[string][][]model.Node {
@@ -1334,29 +1490,79 @@ func ConcurrentElementSearch(elements map[string]model.Element, targets []string
 	results := make(map[string][][]model.Node)
 	resultsMutex := sync.Mutex{}
 
-	maxConcurrency := 4
-	semaphore := make(chan struct{}, maxConcurrency)
+	ConcurrentElementSearchStream(context.Background(), elements, targets, maxResultsPerTarget, singlePath, DefaultSearchConcurrency, func(result TargetResult) {
+		resultsMutex.Lock()
+		results[result.Target] = result.Paths
+		resultsMutex.Unlock()
+	})
+
+	return results
+}
+
+// DefaultSearchConcurrency adalah jumlah target yang dicari bersamaan kalo tidak diatur
+const DefaultSearchConcurrency = 4
+
+// TargetResult adalah hasil HybridSearch untuk satu target di pencarian batch
+type TargetResult struct {
+	Target  string
+	Paths   [][]model.Node
+	Visited int
+	Elapsed time.Duration
+	Err     error
+}
+
+// ConcurrentElementSearchStream menjalankan HybridSearch untuk setiap target dengan
+// paling banyak concurrency pencarian sekaligus. onResult dipanggil begitu satu
+// target selesai (bisa dari goroutine mana saja, tidak bersamaan). Kalo ctx
+// dibatalkan, target yang belum mulai dilaporkan dengan error ctx.
+func ConcurrentElementSearchStream(ctx context.Context, elements map[string]model.Element, targets []string, maxResultsPerTarget int, singlePath bool, concurrency int, onResult func(TargetResult)) {
+	if concurrency <= 0 {
+		concurrency = DefaultSearchConcurrency
+	}
+
+	semaphore := make(chan struct{}, concurrency)
 	var wg sync.WaitGroup
+	var reportMutex sync.Mutex
+
+	report := func(result TargetResult) {
+		reportMutex.Lock()
+		defer reportMutex.Unlock()
+		onResult(result)
+	}
 
 	for _, target := range targets {
-		wg.Add(1)
-		semaphore <- struct{}{}
+		select {
+		case semaphore <- struct{}{}:
+		case <-ctx.Done():
+			report(TargetResult{Target: target, Err: ctx.Err()})
+			continue
+		}
 
+		wg.Add(1)
 		go func(tgt string) {
 			defer wg.Done()
 			defer func() { <-semaphore }()
 
-			paths, _ := HybridSearch(elements, tgt, maxResultsPerTarget, singlePath)
+			startTime := time.Now()
+			result := TargetResult{Target: tgt}
+
+			// satu target yang panic tidak boleh menghentikan batch
+			func() {
+				defer func() {
+					if r := recover(); r != nil {
+						log.Printf("ERROR: Search for %s panicked: %v", tgt, r)
+						result.Err = fmt.Errorf("search failed: %v", r)
+					}
+				}()
+				result.Paths, result.Visited = HybridSearchWithMonitor(elements, tgt, maxResultsPerTarget, singlePath, NewMonitor(ctx, nil))
+			}()
 
-			resultsMutex.Lock()
-			results[tgt] = paths
-			resultsMutex.Unlock()
+			result.Elapsed = time.Since(startTime)
+			report(result)
 		}(target)
 	}
 
 	wg.Wait()
-
-	return results
 }
 
 func FindShortestPath(elements map[string]model.Element, target string) ([]model.Node, int) {
@@ -1366,31 +1572,17 @@ func FindShortestPath(elements map[string]model.Element, target string) ([]model
 		return paths[0], visited
 	}
 
-	return nil, visited
-}
-
-func AnalyzeElementComplexity(elements map[string]model.Element, elementName string) int {
-	path, _ := FindShortestPath(elements, elementName)
-
-	if path == nil {
-		return -1
-	}
-
-	return len(path) - 1
-}
-
-func FindPrerequisiteElements(elements map[string]model.Element, target string) map[string]bool {
-	paths, _ := BidirectionalBFS(elements, target, 5, false)
-	prerequisites := make(map[string]bool)
+	// bidirectional kadang gagal ketemu di tengah untuk elemen yang dalam,
+	// jadi fallback ke BFS biasa
+	log.Printf("DEBUG: Bidirectional found no path for %s, falling back to BFS", target)
+	paths, bfsVisited := BFS(elements, target, 1, true)
+	visited += bfsVisited
 
-	for _, path := range paths {
-		for _, node := range path {
-			prerequisites[node.Element] = true
-		}
+	if len(paths) > 0 {
+		return paths[0], visited
 	}
-	delete(prerequisites, target)
 
-	return prerequisites
+	return nil, visited
 }
 
 func validateIngredientsInPath(path []model.Node) bool {
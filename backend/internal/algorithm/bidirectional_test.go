@@ -0,0 +1,95 @@
+package algorithm_test
+
+import (
+	alg "backend/internal/algorithm"
+	"backend/internal/synthetic"
+	"backend/model"
+	"backend/utils"
+	"io"
+	"log"
+	"os"
+	"testing"
+)
+
+// TestBidirectionalFindsDeepElements memastikan bidirectional menemukan pohon
+// valid untuk setiap elemen di graf sintetis tanpa siklus. Dulu path dari
+// pertemuan dua arah cuma satu rantai elemen, ingredient kedua resep di
+// rantai itu hilang dan semua path untuk elemen yang dalam dibuang.
+func TestBidirectionalFindsDeepElements(t *testing.T) {
+	log.SetOutput(io.Discard)
+	defer log.SetOutput(os.Stderr)
+
+	generated, err := synthetic.Generate(synthetic.Config{Elements: 80, MinRecipes: 1, MaxRecipes: 3, FanIn: 2, Seed: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	elements := synthetic.Map(generated)
+
+	for _, element := range generated[len(synthetic.BaseElements):] {
+		paths, _ := alg.BidirectionalBFS(elements, element.Name, 5, false)
+		valid := 0
+		for _, tree := range utils.BuildTrees(elements, paths, element.Name, 0) {
+			issues := utils.VerifyTree(tree, element.Name, elements, synthetic.BaseElements)
+			for _, issue := range issues {
+				t.Errorf("%s: %s", element.Name, issue)
+			}
+			if len(issues) == 0 {
+				valid++
+			}
+		}
+		if valid == 0 {
+			t.Errorf("no valid tree for %s (tier %d)", element.Name, element.Tier)
+		}
+	}
+}
+
+// TestBidirectionalSkipsUnmakeableElements memastikan pencarian mundur tidak
+// lewat elemen yang tidak bisa dibuat. Wood cuma bisa dibuat dari Tree yang
+// tidak punya resep, jadi House harus lewat Brick + Wall.
+func TestBidirectionalSkipsUnmakeableElements(t *testing.T) {
+	log.SetOutput(io.Discard)
+	defer log.SetOutput(os.Stderr)
+
+	element := func(name string, tier int, recipes ...[]string) model.Element {
+		e := model.Element{Name: name, Tier: tier}
+		for _, ingredients := range recipes {
+			e.Recipes = append(e.Recipes, model.ElementRecipe{Ingredients: ingredients})
+		}
+		return e
+	}
+	elements := map[string]model.Element{}
+	for _, e := range []model.Element{
+		element("Water", 0), element("Fire", 0), element("Earth", 0), element("Air", 0),
+		element("Steam", 1, []string{"Water", "Fire"}),
+		element("Mud", 1, []string{"Water", "Earth"}),
+		element("Clay", 2, []string{"Mud", "Steam"}),
+		element("Brick", 3, []string{"Clay", "Fire"}),
+		element("Wall", 4, []string{"Brick", "Mud"}),
+		element("Tree", 4),
+		element("Wood", 5, []string{"Tree", "Fire"}),
+		element("House", 6, []string{"Wood", "Water"}, []string{"Brick", "Wall"}),
+	} {
+		elements[e.Name] = e
+	}
+
+	if paths, _ := alg.BidirectionalBFS(elements, "Wood", 5, false); len(paths) != 0 {
+		t.Fatalf("Wood cannot be made, got %d paths", len(paths))
+	}
+
+	paths, _ := alg.BidirectionalBFS(elements, "House", 5, false)
+	if len(paths) == 0 {
+		t.Fatal("expected a path for House through Brick + Wall")
+	}
+	for _, path := range paths {
+		for _, node := range path {
+			if node.Element == "Wood" || node.Element == "Tree" {
+				t.Fatalf("path goes through unmakeable %s: %v", node.Element, path)
+			}
+		}
+	}
+	for _, tree := range utils.BuildTrees(elements, paths, "House", 0) {
+		for _, issue := range utils.VerifyTree(tree, "House", elements, []string{"Water", "Fire", "Earth", "Air"}) {
+			t.Error(issue)
+		}
+	}
+}
@@ -11,6 +11,11 @@ import (
 )
 
 func DFS(elements map[string]model.Element, target string, maxResults int, debug bool) ([][]model.Node, int) {
+	return DFSWithMonitor(elements, target, maxResults, debug, nil)
+}
+
+// DFSWithMonitor sama dengan DFS tapi bisa dihentikan dan dipantau lewat monitor
+func DFSWithMonitor(elements map[string]model.Element, target string, maxResults int, debug bool, monitor *Monitor) ([][]model.Node, int) {
 	if debug {
 		log.Printf("DEBUG: Starting ReverseDFS for target: %s (max results: %d)", target, maxResults)
 	}
@@ -56,7 +61,7 @@ func DFS(elements map[string]model.Element, target string, maxResults int, debug
 			{Element: target, ImagePath: targetNode.ImagePath},
 		}
 
-		Explore(g, recipe, path, visited, &visitedCount, &results, maxResults, baseElements, debug)
+		explore(g, recipe, path, visited, &visitedCount, &results, maxResults, baseElements, debug, monitor)
 
 		if len(results) >= maxResults && maxResults > 0 {
 			if debug {
@@ -74,13 +79,23 @@ func DFS(elements map[string]model.Element, target string, maxResults int, debug
 }
 
 func Explore(g *graph.ElementGraph, recipe *graph.Recipe, currentPath []*model.Node, visited map[string]bool, visitedCount *int, results *[][]model.Node, maxResults int, baseElements []string, debug bool) {
+	explore(g, recipe, currentPath, visited, visitedCount, results, maxResults, baseElements, debug, nil)
+}
+
+func explore(g *graph.ElementGraph, recipe *graph.Recipe, currentPath []*model.Node, visited map[string]bool, visitedCount *int, results *[][]model.Node, maxResults int, baseElements []string, debug bool, monitor *Monitor) {
 	if len(*results) >= maxResults && maxResults > 0 {
 		return
 	}
+	if monitor.Cancelled() {
+		return
+	}
 
 	if debug {
 		log.Printf("DEBUG: Exploring recipe: %s from ingredients: %v", recipe.Result, recipe.Ingredients)
 	}
+	// visited berisi elemen di stack rekursi, jadi ukurannya = kedalaman dari target
+	depth := len(visited)
+	monitor.Emit(SearchEvent{Kind: EventExpanded, Element: recipe.Result, Ingredients: recipe.Ingredients, Depth: depth})
 
 	ingredients := recipe.Ingredients
 	if len(ingredients) == 0 {
@@ -99,6 +114,7 @@ func Explore(g *graph.ElementGraph, recipe *graph.Recipe, currentPath []*model.N
 	for _, ingredient := range ingredients {
 		ingredientNode := g.Nodes[ingredient]
 		*visitedCount++
+		monitor.Visit(1)
 
 		ingredientNodeObj := &model.Node{
 			Element:   ingredient,
@@ -132,6 +148,8 @@ func Explore(g *graph.ElementGraph, recipe *graph.Recipe, currentPath []*model.N
 		}
 
 		*results = append(*results, finalPath)
+		monitor.PathFound(finalPath)
+		monitor.Emit(SearchEvent{Kind: EventPathFound, Element: finalPath[len(finalPath)-1].Element, Depth: depth})
 
 		if debug {
 			log.Printf("DEBUG: Found complete path with %d steps", len(finalPath))
@@ -156,6 +174,7 @@ func Explore(g *graph.ElementGraph, recipe *graph.Recipe, currentPath []*model.N
 		}
 
 		if visited[ingredient] {
+			monitor.Emit(SearchEvent{Kind: EventPruned, Element: ingredient, Parent: recipe.Result, Depth: depth + 1, Reason: "cycle"})
 			continue
 		}
 
@@ -169,7 +188,7 @@ func Explore(g *graph.ElementGraph, recipe *graph.Recipe, currentPath []*model.N
 			ingredientPath := make([]*model.Node, len(newPath))
 			copy(ingredientPath, newPath)
 
-			Explore(g, subRecipe, ingredientPath, visited, visitedCount, results, maxResults, baseElements, debug)
+			explore(g, subRecipe, ingredientPath, visited, visitedCount, results, maxResults, baseElements, debug, monitor)
 
 			if len(*results) >= maxResults && maxResults > 0 {
 				break
@@ -266,6 +285,12 @@ func GetElementTreeDFS(g *graph.ElementGraph, elementName string) (map[string]in
 }
 
 func MultiThreadedDFS(elements map[string]model.Element, target string, maxResults int, singlePath bool) ([][]model.Node, int) {
+	return MultiThreadedDFSWithMonitor(elements, target, maxResults, singlePath, nil)
+}
+
+// MultiThreadedDFSWithMonitor sama dengan MultiThreadedDFS tapi bisa dihentikan
+// dan dipantau lewat monitor
+func MultiThreadedDFSWithMonitor(elements map[string]model.Element, target string, maxResults int, singlePath bool, monitor *Monitor) ([][]model.Node, int) {
 	g := graph.NewElementGraph(elements)
 
 	targetNode, exists := g.Nodes[target]
@@ -385,7 +410,7 @@ func MultiThreadedDFS(elements map[string]model.Element, target string, maxResul
 					strat.name, recipeIdx+1, recipe.Ingredients, recipeIdx+1, len(validRecipes))
 
 				exploreWithStrategy(g, recipe, path, localVisited, &localCount, &localResults,
-					maxResults, baseElements, strat.maxDepth, strat.favorSimplicity)
+					maxResults, baseElements, strat.maxDepth, strat.favorSimplicity, monitor)
 
 				if len(localResults) > 0 {
 					log.Printf("DEBUG: Goroutine '%s' found %d paths for recipe %d",
@@ -511,9 +536,9 @@ func MultiThreadedDFS(elements map[string]model.Element, target string, maxResul
 		finalResults = finalResults[:maxResults]
 	}
 
-	if len(finalResults) == 0 {
+	if len(finalResults) == 0 && !monitor.Cancelled() {
 		log.Printf("No paths found in parallel exploration, falling back to standard DFS")
-		return DFS(elements, target, maxResults, false)
+		return DFSWithMonitor(elements, target, maxResults, false, monitor)
 	}
 
 	log.Printf("MultiThreadedDFS found %d unique paths across %d recipe groups after visiting %d nodes",
@@ -532,8 +557,8 @@ func hasDuplicateIngredients(ingredients []string) bool {
 	return false
 }
 
-func exploreWithStrategy(g *graph.ElementGraph, recipe *graph.Recipe, currentPath []*model.Node, visited map[string]bool, visitCount *int, results *[][]model.Node, maxResults int, baseElements []string, maxDepth int, favorSimplicity bool) {
-	if len(currentPath) > maxDepth {
+func exploreWithStrategy(g *graph.ElementGraph, recipe *graph.Recipe, currentPath []*model.Node, visited map[string]bool, visitCount *int, results *[][]model.Node, maxResults int, baseElements []string, maxDepth int, favorSimplicity bool, monitor *Monitor) {
+	if len(currentPath) > maxDepth || monitor.Cancelled() {
 		return
 	}
 
@@ -577,6 +602,7 @@ func exploreWithStrategy(g *graph.ElementGraph, recipe *graph.Recipe, currentPat
 	for idx, ingredient := range ingredients {
 		ingredientNode := g.Nodes[ingredient]
 		*visitCount++
+		monitor.Visit(1)
 
 		ingredientNodeObj := &model.Node{
 			Element:     ingredient,
@@ -618,6 +644,7 @@ func exploreWithStrategy(g *graph.ElementGraph, recipe *graph.Recipe, currentPat
 		}
 
 		*results = append(*results, finalPath)
+		monitor.PathFound(finalPath)
 		return
 	}
 
@@ -723,7 +750,7 @@ func exploreWithStrategy(g *graph.ElementGraph, recipe *graph.Recipe, currentPat
 			copy(ingredientPath, newPath)
 
 			exploreWithStrategy(g, subRecipe, ingredientPath, visited, visitCount,
-				results, maxResults, baseElements, maxDepth, favorSimplicity)
+				results, maxResults, baseElements, maxDepth, favorSimplicity, monitor)
 
 			if len(*results) >= maxResults*10 && maxResults > 0 {
 				break
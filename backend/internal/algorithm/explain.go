@@ -0,0 +1,196 @@
+package algorithm
+
+import (
+	"backend/internal/graph"
+	"backend/utils"
+	"sort"
+)
+
+// alasan kenapa sebuah ingredient menghalangi resep
+const (
+	ReasonMissing           = "missing-from-dataset"
+	ReasonExcludedByTime    = "excluded-by-time-rule"
+	ReasonExcludedByPolicy  = "excluded-by-policy"
+	ReasonTierInvalid       = "tier-invalid"
+	ReasonNoRecipes         = "no-recipes"
+	ReasonAllRecipesRemoved = "all-recipes-rejected"
+	ReasonCyclic            = "cyclic"
+	ReasonUnreachable       = "unreachable"
+)
+
+type BlockedIngredient struct {
+	Name      string   `json:"name"`
+	Reason    string   `json:"reason"`
+	BlockedBy []string `json:"blockedBy,omitempty"`
+}
+
+type RecipeExplanation struct {
+	Ingredients []string            `json:"ingredients"`
+	Buildable   bool                `json:"buildable"`
+	Rejected    bool                `json:"rejected"`
+	Blocked     []BlockedIngredient `json:"blocked,omitempty"`
+}
+
+type Explanation struct {
+	Element   string              `json:"element"`
+	Reachable bool                `json:"reachable"`
+	IsBase    bool                `json:"isBaseElement"`
+	Reason    string              `json:"reason,omitempty"`
+	Recipes   []RecipeExplanation `json:"recipes"`
+	Blockers  []BlockedIngredient `json:"blockers"`
+}
+
+// ExplainElement menjelaskan kenapa elemen tidak bisa dibuat dari elemen dasar:
+// untuk setiap resep (termasuk yang dibuang saat validasi) dicari ingredient
+// mana yang menghalangi dan alasannya.
+func ExplainElement(g *graph.ElementGraph, elementName string, baseElements []string, reachable map[string]bool, report utils.ValidationReport) Explanation {
+	explanation := Explanation{
+		Element:   elementName,
+		Reachable: reachable[elementName],
+		IsBase:    isBaseElement(elementName, baseElements),
+		Recipes:   make([]RecipeExplanation, 0),
+		Blockers:  make([]BlockedIngredient, 0),
+	}
+
+	node := g.Nodes[elementName]
+	if node == nil || explanation.IsBase {
+		return explanation
+	}
+
+	rejectedByElement := make(map[string][]utils.RejectedRecipe)
+	for _, rejected := range report.Rejected {
+		rejectedByElement[rejected.Element] = append(rejectedByElement[rejected.Element], rejected)
+	}
+
+	blockers := make(map[string]BlockedIngredient)
+
+	for _, recipe := range node.RecipesToMakeThisElement {
+		recipeExplanation := RecipeExplanation{
+			Ingredients: recipe.Ingredients,
+			Buildable:   true,
+		}
+
+		for _, ing := range recipe.Ingredients {
+			if reachable[ing] {
+				continue
+			}
+
+			blocked := explainIngredient(g, ing, elementName, reachable, rejectedByElement)
+			recipeExplanation.Buildable = false
+			recipeExplanation.Blocked = append(recipeExplanation.Blocked, blocked)
+			blockers[blocked.Name] = blocked
+		}
+
+		explanation.Recipes = append(explanation.Recipes, recipeExplanation)
+	}
+
+	for _, rejected := range rejectedByElement[elementName] {
+		blocked := BlockedIngredient{
+			Name:   rejected.Ingredient,
+			Reason: rejectionReason(rejected.Policy),
+		}
+
+		explanation.Recipes = append(explanation.Recipes, RecipeExplanation{
+			Ingredients: rejected.Ingredients,
+			Rejected:    true,
+			Blocked:     []BlockedIngredient{blocked},
+		})
+
+		if _, exists := blockers[blocked.Name]; !exists {
+			blockers[blocked.Name] = blocked
+		}
+	}
+
+	if !explanation.Reachable {
+		if len(node.RecipesToMakeThisElement) == 0 {
+			explanation.Reason = ReasonNoRecipes
+			if len(rejectedByElement[elementName]) > 0 {
+				explanation.Reason = ReasonAllRecipesRemoved
+			}
+		}
+
+		for _, blocked := range blockers {
+			explanation.Blockers = append(explanation.Blockers, blocked)
+		}
+		sort.Slice(explanation.Blockers, func(i, j int) bool {
+			return explanation.Blockers[i].Name < explanation.Blockers[j].Name
+		})
+	}
+
+	return explanation
+}
+
+func explainIngredient(g *graph.ElementGraph, ing, target string, reachable map[string]bool, rejectedByElement map[string][]utils.RejectedRecipe) BlockedIngredient {
+	ingNode := g.Nodes[ing]
+	if ingNode == nil {
+		return BlockedIngredient{Name: ing, Reason: ReasonMissing}
+	}
+
+	if len(ingNode.RecipesToMakeThisElement) == 0 {
+		if len(rejectedByElement[ing]) > 0 {
+			return BlockedIngredient{Name: ing, Reason: ReasonAllRecipesRemoved}
+		}
+		return BlockedIngredient{Name: ing, Reason: ReasonNoRecipes}
+	}
+
+	blockedBy := unreachableDependencies(ingNode, reachable)
+	if ing == target || dependsOn(g, ing, target, reachable) || dependsOn(g, ing, ing, reachable) {
+		return BlockedIngredient{Name: ing, Reason: ReasonCyclic, BlockedBy: blockedBy}
+	}
+
+	return BlockedIngredient{Name: ing, Reason: ReasonUnreachable, BlockedBy: blockedBy}
+}
+
+// dependsOn ngecek apakah from (lewat ingredient yang juga tidak bisa dibuat)
+// bergantung ke target.
+func dependsOn(g *graph.ElementGraph, from, target string, reachable map[string]bool) bool {
+	visited := make(map[string]bool)
+	stack := unreachableDependencies(g.Nodes[from], reachable)
+
+	for len(stack) > 0 {
+		current := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		if current == target {
+			return true
+		}
+		if visited[current] {
+			continue
+		}
+		visited[current] = true
+
+		if node := g.Nodes[current]; node != nil {
+			stack = append(stack, unreachableDependencies(node, reachable)...)
+		}
+	}
+
+	return false
+}
+
+func unreachableDependencies(node *graph.ElementGraphNode, reachable map[string]bool) []string {
+	seen := make(map[string]bool)
+	deps := make([]string, 0)
+
+	for _, recipe := range node.RecipesToMakeThisElement {
+		for _, ing := range recipe.Ingredients {
+			if !reachable[ing] && !seen[ing] {
+				seen[ing] = true
+				deps = append(deps, ing)
+			}
+		}
+	}
+
+	sort.Strings(deps)
+	return deps
+}
+
+func rejectionReason(policy string) string {
+	switch policy {
+	case utils.PolicyTimeExclusion:
+		return ReasonExcludedByTime
+	case utils.PolicyTierOrder:
+		return ReasonTierInvalid
+	default:
+		return ReasonExcludedByPolicy
+	}
+}
@@ -0,0 +1,117 @@
+package algorithm
+
+import (
+	"backend/model"
+	"context"
+	"sync"
+	"sync/atomic"
+)
+
+// EventKind adalah jenis kejadian selama pencarian
+type EventKind string
+
+const (
+	EventEnqueued    EventKind = "enqueued"
+	EventExpanded    EventKind = "expanded"
+	EventPruned      EventKind = "pruned"
+	EventFrontierMet EventKind = "frontier-met"
+	EventPathFound   EventKind = "path-found"
+)
+
+// Arah ekspansi di bidirectional search
+const (
+	DirectionForward  = "forward"
+	DirectionBackward = "backward"
+)
+
+// SearchEvent adalah satu langkah eksplorasi yang benar-benar dilakukan algoritma
+type SearchEvent struct {
+	Kind    EventKind `json:"kind"`
+	Element string    `json:"element"`
+	// elemen yang sedang diekspansi waktu Element ditemukan
+	Parent      string   `json:"parent,omitempty"`
+	Ingredients []string `json:"ingredients,omitempty"`
+	Depth       int      `json:"depth"`
+	Direction   string   `json:"direction,omitempty"`
+	Reason      string   `json:"reason,omitempty"`
+}
+
+// Monitor dipakai untuk mengamati dan menghentikan pencarian yang sedang jalan.
+// Semua method aman dipanggil dari banyak goroutine dan aman untuk Monitor nil,
+// jadi algoritma bisa memanggilnya tanpa cek dulu.
+type Monitor struct {
+	ctx     context.Context
+	visited atomic.Int64
+
+	mu      sync.Mutex
+	onPath  func(path []model.Node)
+	onEvent func(event SearchEvent)
+}
+
+// NewMonitor membuat Monitor yang berhenti saat ctx dibatalkan. onPath boleh nil,
+// kalo diisi akan dipanggil (tidak bersamaan) setiap algoritma menemukan path.
+func NewMonitor(ctx context.Context, onPath func(path []model.Node)) *Monitor {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return &Monitor{ctx: ctx, onPath: onPath}
+}
+
+// Cancelled true kalo pencarian harus berhenti secepatnya
+func (m *Monitor) Cancelled() bool {
+	if m == nil {
+		return false
+	}
+	return m.ctx.Err() != nil
+}
+
+// Visit menambah jumlah node yang sudah dikunjungi
+func (m *Monitor) Visit(n int) {
+	if m == nil || n <= 0 {
+		return
+	}
+	m.visited.Add(int64(n))
+}
+
+// Visited mengembalikan jumlah node yang sudah dikunjungi sejauh ini
+func (m *Monitor) Visited() int {
+	if m == nil {
+		return 0
+	}
+	return int(m.visited.Load())
+}
+
+// PathFound melaporkan path yang baru ditemukan. Path ini kandidat, hasil akhir
+// algoritma masih bisa menyaring atau mengurutkan ulang.
+func (m *Monitor) PathFound(path []model.Node) {
+	if m == nil || m.onPath == nil {
+		return
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.onPath(path)
+}
+
+// SetEventHandler memasang handler untuk SearchEvent. Harus dipanggil sebelum
+// pencarian dimulai. Handler dipanggil tidak bersamaan.
+func (m *Monitor) SetEventHandler(onEvent func(event SearchEvent)) {
+	m.onEvent = onEvent
+}
+
+// Tracing true kalo ada yang mendengarkan event, dipakai algoritma supaya tidak
+// menyiapkan event yang tidak dipakai
+func (m *Monitor) Tracing() bool {
+	return m != nil && m.onEvent != nil
+}
+
+// Emit melaporkan satu SearchEvent
+func (m *Monitor) Emit(event SearchEvent) {
+	if !m.Tracing() {
+		return
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.onEvent(event)
+}
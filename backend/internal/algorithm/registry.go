@@ -0,0 +1,68 @@
+package algorithm
+
+import (
+	"backend/model"
+	"sort"
+)
+
+// MonitoredSearchFunc adalah bentuk umum algoritma pencarian resep yang bisa
+// dihentikan dan dipantau lewat Monitor
+type MonitoredSearchFunc func(elements map[string]model.Element, target string, maxResults int, singlePath bool, monitor *Monitor) ([][]model.Node, int)
+
+// RegisteredAlgorithm adalah algoritma yang bisa dipanggil lewat namanya
+type RegisteredAlgorithm struct {
+	Name        string
+	Description string
+	search      MonitoredSearchFunc
+}
+
+// Search menjalankan algoritma tanpa monitor
+func (a RegisteredAlgorithm) Search(elements map[string]model.Element, target string, maxResults int, singlePath bool) ([][]model.Node, int) {
+	return a.search(elements, target, maxResults, singlePath, nil)
+}
+
+// SearchWithMonitor menjalankan algoritma dengan monitor (boleh nil)
+func (a RegisteredAlgorithm) SearchWithMonitor(elements map[string]model.Element, target string, maxResults int, singlePath bool, monitor *Monitor) ([][]model.Node, int) {
+	return a.search(elements, target, maxResults, singlePath, monitor)
+}
+
+var registry = make(map[string]RegisteredAlgorithm)
+
+// RegisterAlgorithm mendaftarkan algoritma baru, nama yang sama akan ditimpa
+func RegisterAlgorithm(name, description string, search MonitoredSearchFunc) {
+	registry[name] = RegisteredAlgorithm{Name: name, Description: description, search: search}
+}
+
+// Algorithms mengembalikan semua algoritma terdaftar terurut berdasarkan nama
+func Algorithms() []RegisteredAlgorithm {
+	algorithms := make([]RegisteredAlgorithm, 0, len(registry))
+	for _, algorithm := range registry {
+		algorithms = append(algorithms, algorithm)
+	}
+	sort.Slice(algorithms, func(i, j int) bool {
+		return algorithms[i].Name < algorithms[j].Name
+	})
+	return algorithms
+}
+
+// LookupAlgorithm mencari algoritma terdaftar berdasarkan nama
+func LookupAlgorithm(name string) (RegisteredAlgorithm, bool) {
+	algorithm, exists := registry[name]
+	return algorithm, exists
+}
+
+func init() {
+	RegisterAlgorithm("bfs", "Breadth-first search", BFSWithMonitor)
+	RegisterAlgorithm("multithreaded-bfs", "Breadth-first search with parallel frontier expansion", MultiThreadedBFSWithMonitor)
+	RegisterAlgorithm("dfs", "Depth-first search from the target back to base elements", func(elements map[string]model.Element, target string, maxResults int, singlePath bool, monitor *Monitor) ([][]model.Node, int) {
+		// DFS tidak punya mode single path, cukup batasi ke satu hasil
+		if singlePath {
+			maxResults = 1
+		}
+		return DFSWithMonitor(elements, target, maxResults, false, monitor)
+	})
+	RegisterAlgorithm("multithreaded-dfs", "Depth-first search with one goroutine per recipe", MultiThreadedDFSWithMonitor)
+	RegisterAlgorithm("bidirectional", "Bidirectional BFS meeting between base elements and the target", BidirectionalBFSWithMonitor)
+	RegisterAlgorithm("multithreaded-bidirectional", "Bidirectional BFS with parallel frontiers", MultiThreadedBidirectionalBFSWithMonitor)
+	RegisterAlgorithm("hybrid", "Multithreaded bidirectional with single-threaded fallback", HybridSearchWithMonitor)
+}
@@ -0,0 +1,50 @@
+package algorithm_test
+
+import (
+	alg "backend/internal/algorithm"
+	"backend/internal/synthetic"
+	"backend/utils"
+	"context"
+	"io"
+	"log"
+	"os"
+	"testing"
+	"time"
+)
+
+// TestSmallSyntheticGraphs menjalankan setiap algoritma untuk setiap elemen
+// di graf sintetis kecil. Setiap pohon yang ditemukan harus valid, dan karena
+// semua elemen di graf tanpa siklus bisa dibuat, BFS (yang menelusuri semua
+// resep) harus menemukan paling tidak satu. Algoritma lain boleh berhenti
+// lebih awal.
+func TestSmallSyntheticGraphs(t *testing.T) {
+	log.SetOutput(io.Discard)
+	defer log.SetOutput(os.Stderr)
+
+	for _, seed := range []int64{1, 2, 3} {
+		generated, err := synthetic.Generate(synthetic.Config{Elements: 30, MinRecipes: 1, MaxRecipes: 3, FanIn: 2, Seed: seed})
+		if err != nil {
+			t.Fatal(err)
+		}
+		elements := synthetic.Map(generated)
+
+		for _, algorithm := range alg.Algorithms() {
+			for _, element := range generated[len(synthetic.BaseElements):] {
+				ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+				paths, _ := algorithm.SearchWithMonitor(elements, element.Name, 5, false, alg.NewMonitor(ctx, nil))
+				cancel()
+
+				trees := utils.BuildTrees(elements, paths, element.Name, 0)
+				if len(trees) == 0 && algorithm.Name == "bfs" {
+					t.Errorf("seed %d, %s: no recipe for %s (tier %d)", seed, algorithm.Name, element.Name, element.Tier)
+					continue
+				}
+				for _, tree := range trees {
+					for _, issue := range utils.VerifyTree(tree, element.Name, elements, synthetic.BaseElements) {
+						t.Errorf("seed %d, %s: %s", seed, algorithm.Name, issue)
+					}
+				}
+			}
+		}
+	}
+}
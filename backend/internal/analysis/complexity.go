@@ -0,0 +1,218 @@
+package analysis
+
+import (
+	"backend/internal/graph"
+	"math/big"
+	"sort"
+)
+
+// Complexity adalah ukuran seberapa susah sebuah elemen dibuat
+type Complexity struct {
+	Name      string `json:"name"`
+	Reachable bool   `json:"reachable"`
+	// kedalaman pohon resep terpendek, -1 kalo tidak bisa dibuat
+	ShortestDepth int `json:"shortestDepth"`
+	// jumlah kombinasi paling sedikit di satu pohon resep (ingredient yang
+	// dipakai dua kali dihitung dua kali), -1 kalo tidak bisa dibuat
+	MinCombinations int `json:"minCombinations"`
+	RecipeCount     int `json:"recipeCount"`
+	// jumlah pohon resep berbeda sampai ke elemen dasar
+	DistinctTrees *big.Int `json:"distinctTrees"`
+	// true kalo ada siklus resep yang dipotong (atau batas hitungan tercapai)
+	// saat menghitung DistinctTrees
+	Approximate bool `json:"approximate,omitempty"`
+}
+
+// ComputeComplexity menghitung Complexity untuk semua elemen di graf
+func ComputeComplexity(g *graph.ElementGraph) map[string]*Complexity {
+	reachable := g.ReachableFrom(g.BaseElements)
+	depths := g.ShortestDepths(g.BaseElements)
+	combinations := minCombinations(g)
+	counter := newTreeCounter(g, reachable)
+
+	result := make(map[string]*Complexity, len(g.Nodes))
+	for name, node := range g.Nodes {
+		complexity := &Complexity{
+			Name:            name,
+			Reachable:       reachable[name],
+			ShortestDepth:   -1,
+			MinCombinations: -1,
+			RecipeCount:     len(node.RecipesToMakeThisElement),
+			DistinctTrees:   big.NewInt(0),
+		}
+
+		if depth, exists := depths[name]; exists {
+			complexity.ShortestDepth = depth
+		}
+		if cost, exists := combinations[name]; exists {
+			complexity.MinCombinations = cost
+		}
+		if complexity.Reachable {
+			complexity.DistinctTrees, complexity.Approximate = counter.count(name)
+		}
+
+		result[name] = complexity
+	}
+
+	return result
+}
+
+// RankComplexity mengurutkan elemen yang bisa dibuat dari yang paling susah.
+// sortBy: "depth" (default), "combinations", "trees" atau "recipes".
+func RankComplexity(complexities map[string]*Complexity, sortBy string) []*Complexity {
+	ranked := make([]*Complexity, 0, len(complexities))
+	for _, complexity := range complexities {
+		if complexity.Reachable {
+			ranked = append(ranked, complexity)
+		}
+	}
+
+	sort.Slice(ranked, func(i, j int) bool {
+		a, b := ranked[i], ranked[j]
+		switch sortBy {
+		case "combinations":
+			if a.MinCombinations != b.MinCombinations {
+				return a.MinCombinations > b.MinCombinations
+			}
+		case "trees":
+			if cmp := a.DistinctTrees.Cmp(b.DistinctTrees); cmp != 0 {
+				// makin sedikit pilihan pohon makin susah
+				return cmp < 0
+			}
+		case "recipes":
+			if a.RecipeCount != b.RecipeCount {
+				return a.RecipeCount < b.RecipeCount
+			}
+		}
+		if a.ShortestDepth != b.ShortestDepth {
+			return a.ShortestDepth > b.ShortestDepth
+		}
+		if a.MinCombinations != b.MinCombinations {
+			return a.MinCombinations > b.MinCombinations
+		}
+		return a.Name < b.Name
+	})
+
+	return ranked
+}
+
+// minCombinations menghitung jumlah kombinasi minimum untuk setiap elemen:
+// elemen dasar 0, selain itu 1 + jumlah biaya ingredient pada resep termurah.
+func minCombinations(g *graph.ElementGraph) map[string]int {
+	costs := make(map[string]int, len(g.Nodes))
+	for _, base := range g.BaseElements {
+		costs[base] = 0
+	}
+
+	changed := true
+	for changed {
+		changed = false
+		for name, node := range g.Nodes {
+			for _, recipe := range node.RecipesToMakeThisElement {
+				if len(recipe.Ingredients) == 0 {
+					continue
+				}
+
+				total, ok := 1, true
+				for _, ing := range recipe.Ingredients {
+					cost, exists := costs[ing]
+					if !exists {
+						ok = false
+						break
+					}
+					total += cost
+				}
+
+				if ok {
+					if current, exists := costs[name]; !exists || total < current {
+						costs[name] = total
+						changed = true
+					}
+				}
+			}
+		}
+	}
+
+	return costs
+}
+
+// treeCounter menghitung jumlah pohon resep berbeda. Elemen yang sedang
+// diekspansi tidak boleh muncul lagi di bawahnya, jadi cabang yang kembali ke
+// elemen itu (siklus) dihitung nol.
+type treeCounter struct {
+	g         *graph.ElementGraph
+	reachable map[string]bool
+	base      map[string]bool
+	cache     map[string]*big.Int
+	inStack   map[string]bool
+	budget    int
+}
+
+// treeCountBudget membatasi jumlah kunjungan per elemen supaya graf dengan
+// banyak siklus (misal tanpa policy tier-order) tidak meledak eksponensial.
+const treeCountBudget = 200000
+
+func newTreeCounter(g *graph.ElementGraph, reachable map[string]bool) *treeCounter {
+	base := make(map[string]bool, len(g.BaseElements))
+	for _, name := range g.BaseElements {
+		base[name] = true
+	}
+
+	return &treeCounter{
+		g:         g,
+		reachable: reachable,
+		base:      base,
+		cache:     make(map[string]*big.Int),
+		inStack:   make(map[string]bool),
+	}
+}
+
+func (c *treeCounter) count(name string) (*big.Int, bool) {
+	c.budget = treeCountBudget
+	total, cut := c.visit(name)
+	return new(big.Int).Set(total), cut
+}
+
+func (c *treeCounter) visit(name string) (*big.Int, bool) {
+	if c.base[name] {
+		return big.NewInt(1), false
+	}
+	if cached, exists := c.cache[name]; exists {
+		return cached, false
+	}
+	if c.inStack[name] || !c.reachable[name] {
+		return big.NewInt(0), c.inStack[name]
+	}
+	if c.budget <= 0 {
+		return big.NewInt(0), true
+	}
+	c.budget--
+
+	c.inStack[name] = true
+	defer delete(c.inStack, name)
+
+	total := big.NewInt(0)
+	cut := false
+	for _, recipe := range c.g.Nodes[name].RecipesToMakeThisElement {
+		if len(recipe.Ingredients) == 0 {
+			continue
+		}
+
+		product := big.NewInt(1)
+		for _, ing := range recipe.Ingredients {
+			ways, ingCut := c.visit(ing)
+			cut = cut || ingCut
+			product.Mul(product, ways)
+			if product.Sign() == 0 {
+				break
+			}
+		}
+		total.Add(total, product)
+	}
+
+	// hasil yang kena potong siklus tergantung jalur, jadi tidak di-cache
+	if !cut {
+		c.cache[name] = total
+	}
+	return total, cut
+}
@@ -0,0 +1,242 @@
+package analysis
+
+import (
+	"backend/internal/graph"
+	"math"
+	"sort"
+)
+
+// Importance adalah seberapa penting sebuah elemen buat elemen lain di dataset
+type Importance struct {
+	Name string `json:"name"`
+	// jumlah elemen lain yang jadi tidak bisa dibuat kalo elemen ini dihapus
+	DependentsLost int `json:"dependentsLost"`
+	// jumlah elemen lain yang paling tidak satu pohon resep terpendeknya
+	// memakai elemen ini
+	TreeAppearances int `json:"treeAppearances"`
+	// rata-rata porsi pohon resep terpendek elemen lain yang memakai elemen
+	// ini (centrality gaya betweenness), dari 0 sampai 1
+	Centrality float64 `json:"centrality"`
+}
+
+// ImportanceReport menyimpan hasil analisis kepentingan untuk satu dataset
+type ImportanceReport struct {
+	Elements   map[string]*Importance
+	dependents map[string][]string
+	dominators map[string][]string
+	reachable  map[string]bool
+}
+
+// ComputeImportance menganalisis setiap elemen yang bisa dibuat:
+//   - dependents: elemen yang tidak bisa dibuat lagi kalo elemen ini dihapus
+//   - dominators: kebalikannya, elemen yang selalu muncul di setiap pohon resep target
+//   - centrality: porsi pohon resep terpendek elemen lain yang memakai elemen ini,
+//     dihitung atas semua pohon terpendek (lihat shortestTreeShares)
+func ComputeImportance(g *graph.ElementGraph) *ImportanceReport {
+	report := &ImportanceReport{
+		Elements:   make(map[string]*Importance),
+		dependents: make(map[string][]string),
+		dominators: make(map[string][]string),
+	}
+
+	reachable := g.ReachableFrom(g.BaseElements)
+	report.reachable = reachable
+
+	for name := range reachable {
+		without := g.ReachableWithout(g.BaseElements, name)
+
+		lost := make([]string, 0)
+		for other := range reachable {
+			if other != name && !without[other] {
+				lost = append(lost, other)
+				report.dominators[other] = append(report.dominators[other], name)
+			}
+		}
+		sort.Strings(lost)
+
+		report.dependents[name] = lost
+		report.Elements[name] = &Importance{
+			Name:           name,
+			DependentsLost: len(lost),
+		}
+	}
+
+	for _, doms := range report.dominators {
+		sort.Strings(doms)
+	}
+
+	shares, appearances := shortestTreeShares(g, reachable)
+	others := float64(len(reachable) - 1)
+	for name, importance := range report.Elements {
+		importance.TreeAppearances = appearances[name]
+		if others > 0 {
+			importance.Centrality = shares[name] / others
+		}
+	}
+
+	return report
+}
+
+// Dominators mengembalikan elemen yang ada di setiap pohon resep valid untuk target
+func (r *ImportanceReport) Dominators(target string) []string {
+	if doms, exists := r.dominators[target]; exists {
+		return doms
+	}
+	return []string{}
+}
+
+// Dependents mengembalikan elemen yang tidak bisa dibuat tanpa name
+func (r *ImportanceReport) Dependents(name string) []string {
+	if deps, exists := r.dependents[name]; exists {
+		return deps
+	}
+	return []string{}
+}
+
+// Ranked mengembalikan elemen terurut dari yang paling penting menurut sortBy
+// ("dependents" atau "centrality").
+func (r *ImportanceReport) Ranked(sortBy string) []*Importance {
+	ranked := make([]*Importance, 0, len(r.Elements))
+	for _, importance := range r.Elements {
+		ranked = append(ranked, importance)
+	}
+
+	sort.Slice(ranked, func(i, j int) bool {
+		a, b := ranked[i], ranked[j]
+		if sortBy == "centrality" && a.Centrality != b.Centrality {
+			return a.Centrality > b.Centrality
+		}
+		if a.DependentsLost != b.DependentsLost {
+			return a.DependentsLost > b.DependentsLost
+		}
+		if a.Centrality != b.Centrality {
+			return a.Centrality > b.Centrality
+		}
+		return a.Name < b.Name
+	})
+
+	return ranked
+}
+
+// shortestTreeShares menghitung centrality gaya betweenness di hypergraph resep.
+// Pohon resep terpendek target adalah pohon yang setiap node-nya dibuat dengan
+// resep yang memberi kedalaman terpendek. Target biasanya punya banyak pohon
+// seperti itu, jadi untuk setiap pasangan (elemen, target) yang dihitung porsi
+// pohon terpendek target yang memakai elemen itu, mirip sigma_st(v)/sigma_st di
+// betweenness biasa. Hasilnya jumlah porsi itu per elemen dan jumlah target
+// yang porsinya lebih dari 0, tidak tergantung urutan resep.
+func shortestTreeShares(g *graph.ElementGraph, reachable map[string]bool) (map[string]float64, map[string]int) {
+	depths := g.ShortestDepths(g.BaseElements)
+
+	// resep terpendek per elemen beserta bobotnya, yaitu porsi pohon terpendek
+	// elemen itu yang memakai resep tersebut. Jumlah pohon tumbuh terlalu cepat
+	// untuk float64, jadi dihitung dalam log.
+	type shortestRecipe struct {
+		ingredients []string
+		weight      float64
+	}
+	logTrees := make(map[string]float64, len(depths))
+	recipes := make(map[string][]shortestRecipe, len(depths))
+
+	var countTrees func(name string) float64
+	countTrees = func(name string) float64 {
+		if count, done := logTrees[name]; done {
+			return count
+		}
+		if depths[name] == 0 {
+			logTrees[name] = 0
+			return 0
+		}
+
+		logCounts := make([]float64, 0)
+		for _, recipe := range g.Nodes[name].RecipesToMakeThisElement {
+			deepest, ok := 0, len(recipe.Ingredients) > 0
+			for _, ing := range recipe.Ingredients {
+				d, exists := depths[ing]
+				if !exists {
+					ok = false
+					break
+				}
+				deepest = max(deepest, d)
+			}
+			if !ok || deepest+1 != depths[name] {
+				continue
+			}
+
+			// ingredient selalu lebih dangkal, jadi rekursi ini tidak bisa berputar
+			logCount := 0.0
+			for _, ing := range recipe.Ingredients {
+				logCount += countTrees(ing)
+			}
+			recipes[name] = append(recipes[name], shortestRecipe{ingredients: recipe.Ingredients})
+			logCounts = append(logCounts, logCount)
+		}
+
+		largest := math.Inf(-1)
+		for _, logCount := range logCounts {
+			largest = max(largest, logCount)
+		}
+		sum := 0.0
+		for _, logCount := range logCounts {
+			sum += math.Exp(logCount - largest)
+		}
+		for i, logCount := range logCounts {
+			recipes[name][i].weight = math.Exp(logCount-largest) / sum
+		}
+
+		logTrees[name] = largest + math.Log(sum)
+		return logTrees[name]
+	}
+	for name := range depths {
+		countTrees(name)
+	}
+
+	shares := make(map[string]float64, len(reachable))
+	appearances := make(map[string]int, len(reachable))
+
+	for removed := range reachable {
+		// porsi pohon terpendek yang tidak memakai removed, tepat 1 kalo
+		// removed tidak ada di resep terpendek mana pun di bawah elemen itu
+		without := make(map[string]float64, len(depths))
+		var avoid func(name string) float64
+		avoid = func(name string) float64 {
+			if name == removed {
+				return 0
+			}
+			if share, done := without[name]; done {
+				return share
+			}
+
+			share, uses := 0.0, false
+			for _, recipe := range recipes[name] {
+				product := recipe.weight
+				for _, ing := range recipe.ingredients {
+					ingShare := avoid(ing)
+					if ingShare != 1 {
+						uses = true
+					}
+					product *= ingShare
+				}
+				share += product
+			}
+			if !uses {
+				share = 1
+			}
+
+			without[name] = share
+			return share
+		}
+
+		for target := range reachable {
+			if target == removed {
+				continue
+			}
+			if share := 1 - avoid(target); share > 0 {
+				shares[removed] += share
+				appearances[removed]++
+			}
+		}
+	}
+
+	return shares, appearances
+}
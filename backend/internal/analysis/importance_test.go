@@ -0,0 +1,63 @@
+package analysis
+
+import (
+	"backend/internal/graph"
+	"backend/model"
+	"math"
+	"testing"
+)
+
+// Target punya dua pohon resep terpendek (Steam + Water dan Dust + Water),
+// jadi Steam dan Dust masing-masing dipakai setengah pohon Target. Hasilnya
+// tidak boleh tergantung urutan resep.
+func TestCentralityCountsAllShortestTrees(t *testing.T) {
+	recipes := [][]string{{"Steam", "Water"}, {"Dust", "Water"}}
+
+	for _, order := range [][][]string{recipes, {recipes[1], recipes[0]}} {
+		elements := map[string]model.Element{
+			"Water": {Name: "Water"},
+			"Fire":  {Name: "Fire"},
+			"Earth": {Name: "Earth"},
+			"Air":   {Name: "Air"},
+			"Steam": {Name: "Steam", Tier: 1, Recipes: []model.ElementRecipe{{Ingredients: []string{"Water", "Fire"}}}},
+			"Dust":  {Name: "Dust", Tier: 1, Recipes: []model.ElementRecipe{{Ingredients: []string{"Earth", "Air"}}}},
+			// resep lebih dalam tidak termasuk pohon terpendek
+			"Mud":    {Name: "Mud", Tier: 2, Recipes: []model.ElementRecipe{{Ingredients: []string{"Dust", "Water"}}}},
+			"Target": {Name: "Target", Tier: 2},
+		}
+		target := elements["Target"]
+		for _, ingredients := range append(order, []string{"Mud", "Fire"}) {
+			target.Recipes = append(target.Recipes, model.ElementRecipe{Ingredients: ingredients})
+		}
+		elements["Target"] = target
+
+		report := ComputeImportance(graph.NewElementGraph(elements))
+		others := float64(len(elements) - 1)
+
+		tests := []struct {
+			name        string
+			share       float64
+			appearances int
+		}{
+			// Target
+			{"Steam", 0.5, 1},
+			// Mud dan Target
+			{"Dust", 1 + 0.5, 2},
+			// Steam, Mud dan semua pohon Target
+			{"Water", 1 + 1 + 1, 3},
+			// Steam dan setengah pohon Target
+			{"Fire", 1 + 0.5, 2},
+			{"Mud", 0, 0},
+			{"Target", 0, 0},
+		}
+		for _, test := range tests {
+			importance := report.Elements[test.name]
+			if importance.TreeAppearances != test.appearances {
+				t.Errorf("%s appears in %d shortest trees, expected %d", test.name, importance.TreeAppearances, test.appearances)
+			}
+			if want := test.share / others; math.Abs(importance.Centrality-want) > 1e-9 {
+				t.Errorf("%s has centrality %g, expected %g", test.name, importance.Centrality, want)
+			}
+		}
+	}
+}
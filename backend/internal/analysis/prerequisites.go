@@ -0,0 +1,88 @@
+package analysis
+
+import (
+	"backend/internal/graph"
+	"sort"
+)
+
+// Prerequisites adalah elemen yang bisa muncul di pohon resep valid sebuah target
+type Prerequisites struct {
+	Element   string `json:"element"`
+	Reachable bool   `json:"reachable"`
+	// ada di setiap pohon resep valid untuk target
+	Mandatory []string `json:"mandatory"`
+	// ada di minimal satu pohon resep valid tapi bisa dihindari
+	Optional []string `json:"optional"`
+}
+
+// Prerequisites menghitung secara exact elemen mana yang wajib dan mana yang
+// opsional untuk membuat target. Elemen wajib adalah dominator target yang
+// sudah dihitung ComputeImportance, ancestor lainnya opsional.
+func (r *ImportanceReport) Prerequisites(g *graph.ElementGraph, target string) Prerequisites {
+	result := Prerequisites{
+		Element:   target,
+		Mandatory: make([]string, 0),
+		Optional:  make([]string, 0),
+	}
+
+	if !r.reachable[target] {
+		return result
+	}
+	result.Reachable = true
+
+	mandatory := make(map[string]bool)
+	for _, dominator := range r.Dominators(target) {
+		mandatory[dominator] = true
+	}
+	for _, candidate := range usableAncestors(g, target, r.reachable) {
+		if mandatory[candidate] {
+			result.Mandatory = append(result.Mandatory, candidate)
+		} else {
+			result.Optional = append(result.Optional, candidate)
+		}
+	}
+
+	return result
+}
+
+// usableAncestors mengembalikan semua elemen yang bisa muncul di bawah target
+// pada pohon resep valid, yaitu lewat resep yang semua ingredient-nya bisa dibuat.
+func usableAncestors(g *graph.ElementGraph, target string, reachable map[string]bool) []string {
+	seen := map[string]bool{target: true}
+	queue := []string{target}
+	ancestors := make([]string, 0)
+
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+
+		node := g.Nodes[current]
+		if node == nil {
+			continue
+		}
+
+		for _, recipe := range node.RecipesToMakeThisElement {
+			usable := len(recipe.Ingredients) > 0
+			for _, ing := range recipe.Ingredients {
+				if !reachable[ing] {
+					usable = false
+					break
+				}
+			}
+			if !usable {
+				continue
+			}
+
+			for _, ing := range recipe.Ingredients {
+				if !seen[ing] {
+					seen[ing] = true
+					ancestors = append(ancestors, ing)
+					queue = append(queue, ing)
+				}
+			}
+		}
+	}
+
+	sort.Strings(ancestors)
+	return ancestors
+}
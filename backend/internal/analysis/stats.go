@@ -0,0 +1,106 @@
+package analysis
+
+import (
+	"backend/internal/graph"
+	"backend/model"
+	"sort"
+)
+
+// UsageCount adalah jumlah resep yang memakai sebuah elemen sebagai ingredient
+type UsageCount struct {
+	Name string `json:"name"`
+	Uses int    `json:"uses"`
+}
+
+// Stats adalah statistik global satu dataset
+type Stats struct {
+	TotalElements       int          `json:"totalElements"`
+	TotalRecipes        int          `json:"totalRecipes"`
+	ElementsPerTier     map[int]int  `json:"elementsPerTier"`
+	RecipesPerElement   map[int]int  `json:"recipesPerElement"`
+	MostUsedIngredients []UsageCount `json:"mostUsedIngredients"`
+	SingleRecipeCount   int          `json:"singleRecipeCount"`
+	SingleRecipe        []string     `json:"singleRecipeElements"`
+	ReachableCount      int          `json:"reachableCount"`
+	UnreachableCount    int          `json:"unreachableCount"`
+	AverageDepth        float64      `json:"averageShortestDepth"`
+	MaxDepth            int          `json:"maxShortestDepth"`
+	DeepestElements     []string     `json:"deepestElements"`
+}
+
+// MostUsedLimit adalah jumlah ingredient yang ditampilkan di MostUsedIngredients
+const MostUsedLimit = 20
+
+func ComputeStats(elements map[string]model.Element, g *graph.ElementGraph) *Stats {
+	stats := &Stats{
+		TotalElements:       len(elements),
+		ElementsPerTier:     make(map[int]int),
+		RecipesPerElement:   make(map[int]int),
+		MostUsedIngredients: make([]UsageCount, 0, MostUsedLimit),
+		SingleRecipe:        make([]string, 0),
+		DeepestElements:     make([]string, 0),
+	}
+
+	usage := make([]UsageCount, 0, len(g.Nodes))
+
+	for name, element := range elements {
+		stats.ElementsPerTier[element.Tier]++
+
+		recipeCount := 0
+		if node := g.Nodes[name]; node != nil {
+			recipeCount = len(node.RecipesToMakeThisElement)
+		}
+		stats.TotalRecipes += recipeCount
+		stats.RecipesPerElement[recipeCount]++
+
+		if recipeCount == 1 {
+			stats.SingleRecipe = append(stats.SingleRecipe, name)
+		}
+
+		if uses := g.CountUses(name); uses > 0 {
+			usage = append(usage, UsageCount{Name: name, Uses: uses})
+		}
+	}
+
+	sort.Strings(stats.SingleRecipe)
+	stats.SingleRecipeCount = len(stats.SingleRecipe)
+
+	sort.Slice(usage, func(i, j int) bool {
+		if usage[i].Uses != usage[j].Uses {
+			return usage[i].Uses > usage[j].Uses
+		}
+		return usage[i].Name < usage[j].Name
+	})
+	if len(usage) > MostUsedLimit {
+		usage = usage[:MostUsedLimit]
+	}
+	stats.MostUsedIngredients = append(stats.MostUsedIngredients, usage...)
+
+	depths := g.ShortestDepths(g.BaseElements)
+	stats.ReachableCount = len(depths)
+	stats.UnreachableCount = len(g.Nodes) - len(depths)
+
+	totalDepth, crafted := 0, 0
+	for name, depth := range depths {
+		if depth > 0 {
+			totalDepth += depth
+			crafted++
+		}
+
+		if depth > stats.MaxDepth {
+			stats.MaxDepth = depth
+			stats.DeepestElements = stats.DeepestElements[:0]
+		}
+		if depth == stats.MaxDepth {
+			stats.DeepestElements = append(stats.DeepestElements, name)
+		}
+	}
+	sort.Strings(stats.DeepestElements)
+
+	// rata-rata hanya untuk elemen hasil kombinasi, elemen dasar tidak dihitung
+	if crafted > 0 {
+		stats.AverageDepth = float64(totalDepth) / float64(crafted)
+	}
+
+	return stats
+}
@@ -0,0 +1,194 @@
+// Package bench mengukur semua algoritma pencarian resep di banyak elemen
+// sekaligus, supaya perubahan di algoritma bisa dibandingkan dengan hasil
+// sebelumnya (baseline).
+package bench
+
+import (
+	alg "backend/internal/algorithm"
+	"backend/model"
+	"backend/utils"
+	"context"
+	"fmt"
+	"runtime"
+	"sort"
+	"time"
+)
+
+var baseElements = []string{"Water", "Fire", "Earth", "Air"}
+
+// Config menentukan algoritma dan elemen yang diukur
+type Config struct {
+	Algorithms []string      `json:"algorithms"`
+	Targets    []string      `json:"targets"`
+	MaxResults int           `json:"maxResults"`
+	SinglePath bool          `json:"singlePath"`
+	Timeout    time.Duration `json:"timeout"`
+	// jumlah pengulangan per pasangan, waktu yang dicatat yang paling cepat
+	Repeat int `json:"repeat"`
+}
+
+// Result adalah hasil satu algoritma untuk satu elemen
+type Result struct {
+	Algorithm string `json:"algorithm"`
+	Element   string `json:"element"`
+	Tier      int    `json:"tier"`
+	// kualitas hasil: ada resep atau tidak, jumlah path, dan jumlah kombinasi
+	// paling sedikit di antara path yang ditemukan (-1 kalo tidak ketemu)
+	Found     bool `json:"found"`
+	Paths     int  `json:"paths"`
+	BestSteps int  `json:"bestSteps"`
+
+	NodesVisited int    `json:"nodesVisited"`
+	TimeNs       int64  `json:"timeNs"`
+	Allocs       uint64 `json:"allocs"`
+	AllocBytes   uint64 `json:"allocBytes"`
+	TimedOut     bool   `json:"timedOut,omitempty"`
+}
+
+// Key mengenali pasangan algoritma dan elemen, dipakai saat membandingkan
+func (r Result) Key() string {
+	return r.Algorithm + "\x00" + r.Element
+}
+
+// Targets mengembalikan semua elemen selain elemen dasar, terurut
+func Targets(elements map[string]model.Element) []string {
+	targets := make([]string, 0, len(elements))
+	for name := range elements {
+		if !utils.IsBaseElementName(name, baseElements) {
+			targets = append(targets, name)
+		}
+	}
+	sort.Strings(targets)
+	return targets
+}
+
+// SampleByTier mengambil paling banyak perTier elemen dari setiap tier dengan
+// jarak yang sama di urutan nama, jadi sampelnya selalu sama untuk dataset
+// yang sama
+func SampleByTier(elements map[string]model.Element, perTier int) []string {
+	byTier := make(map[int][]string)
+	for _, name := range Targets(elements) {
+		tier := elements[name].Tier
+		byTier[tier] = append(byTier[tier], name)
+	}
+
+	tiers := make([]int, 0, len(byTier))
+	for tier := range byTier {
+		tiers = append(tiers, tier)
+	}
+	sort.Ints(tiers)
+
+	sample := make([]string, 0, len(tiers)*perTier)
+	for _, tier := range tiers {
+		names := byTier[tier]
+		if perTier >= len(names) {
+			sample = append(sample, names...)
+			continue
+		}
+		for i := 0; i < perTier; i++ {
+			sample = append(sample, names[i*len(names)/perTier])
+		}
+	}
+	return sample
+}
+
+// Run menjalankan setiap algoritma untuk setiap target secara berurutan
+// (supaya waktu dan alokasi tidak saling mengganggu). progress dipanggil
+// setelah setiap pasangan selesai, boleh nil.
+func Run(elements map[string]model.Element, config Config, progress func(done, total int, result Result)) ([]Result, error) {
+	algorithms := make([]alg.RegisteredAlgorithm, 0, len(config.Algorithms))
+	for _, name := range config.Algorithms {
+		algorithm, exists := alg.LookupAlgorithm(name)
+		if !exists {
+			return nil, fmt.Errorf("unknown algorithm %q", name)
+		}
+		algorithms = append(algorithms, algorithm)
+	}
+	for _, target := range config.Targets {
+		if _, exists := elements[target]; !exists {
+			return nil, fmt.Errorf("element %q not found", target)
+		}
+	}
+	if config.Repeat < 1 {
+		config.Repeat = 1
+	}
+
+	total := len(algorithms) * len(config.Targets)
+	results := make([]Result, 0, total)
+	for _, algorithm := range algorithms {
+		for _, target := range config.Targets {
+			result := measure(elements, algorithm, target, config)
+			results = append(results, result)
+			if progress != nil {
+				progress(len(results), total, result)
+			}
+		}
+	}
+	return results, nil
+}
+
+func measure(elements map[string]model.Element, algorithm alg.RegisteredAlgorithm, target string, config Config) Result {
+	result := Result{
+		Algorithm: algorithm.Name,
+		Element:   target,
+		Tier:      elements[target].Tier,
+		BestSteps: -1,
+	}
+
+	var totalAllocs, totalBytes uint64
+	for i := 0; i < config.Repeat; i++ {
+		ctx := context.Background()
+		cancel := func() {}
+		if config.Timeout > 0 {
+			ctx, cancel = context.WithTimeout(ctx, config.Timeout)
+		}
+		monitor := alg.NewMonitor(ctx, nil)
+
+		var before, after runtime.MemStats
+		runtime.GC()
+		runtime.ReadMemStats(&before)
+		start := time.Now()
+		paths, visited := algorithm.SearchWithMonitor(elements, target, config.MaxResults, config.SinglePath, monitor)
+		elapsed := time.Since(start)
+		runtime.ReadMemStats(&after)
+		timedOut := ctx.Err() != nil
+		cancel()
+
+		totalAllocs += after.Mallocs - before.Mallocs
+		totalBytes += after.TotalAlloc - before.TotalAlloc
+
+		// semua statistik diambil dari run yang waktunya dicatat, supaya
+		// satu Result tidak mencampur hasil dari run yang berbeda
+		if i == 0 || elapsed.Nanoseconds() < result.TimeNs {
+			result.TimeNs = elapsed.Nanoseconds()
+			result.NodesVisited = visited
+			result.TimedOut = timedOut
+			result.Paths = len(paths)
+			result.Found = len(paths) > 0
+			result.BestSteps = bestSteps(paths)
+		}
+	}
+	result.Allocs = totalAllocs / uint64(config.Repeat)
+	result.AllocBytes = totalBytes / uint64(config.Repeat)
+
+	return result
+}
+
+// bestSteps menghitung kombinasi paling sedikit di antara path. Tidak semua
+// algoritma mengisi Ingredients di node, jadi setiap elemen bukan dasar yang
+// berbeda di path dihitung satu kombinasi.
+func bestSteps(paths [][]model.Node) int {
+	best := -1
+	for _, path := range paths {
+		made := make(map[string]bool, len(path))
+		for _, node := range path {
+			if !utils.IsBaseElementName(node.Element, baseElements) {
+				made[node.Element] = true
+			}
+		}
+		if best < 0 || len(made) < best {
+			best = len(made)
+		}
+	}
+	return best
+}
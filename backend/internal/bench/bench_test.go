@@ -0,0 +1,106 @@
+package bench
+
+import (
+	"backend/internal"
+	alg "backend/internal/algorithm"
+	"backend/model"
+	"backend/utils"
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// batas waktu satu pencarian, beberapa elemen di tier tinggi bisa sangat
+// lama untuk BFS biasa
+const benchmarkTimeout = 10 * time.Second
+
+// BenchmarkAlgorithms menjalankan setiap algoritma untuk satu elemen dari
+// setiap tier:
+//
+//	go test ./internal/bench -bench . -run ^$
+//	go test ./internal/bench -bench 'Algorithms/bidirectional/' -run ^$ -count 10 > new.txt
+func BenchmarkAlgorithms(b *testing.B) {
+	log.SetOutput(io.Discard)
+	defer log.SetOutput(os.Stderr)
+
+	dataset, err := internal.LoadDataset("../../elements.json", utils.DefaultValidationPolicy())
+	if err != nil {
+		b.Skipf("dataset not available: %v", err)
+	}
+	targets := SampleByTier(dataset.Elements, 1)
+
+	for _, algorithm := range alg.Algorithms() {
+		b.Run(algorithm.Name, func(b *testing.B) {
+			for _, target := range targets {
+				b.Run(target, func(b *testing.B) {
+					b.ReportAllocs()
+					var visited, steps int
+					for b.Loop() {
+						ctx, cancel := context.WithTimeout(context.Background(), benchmarkTimeout)
+						paths, nodes := algorithm.SearchWithMonitor(dataset.Elements, target, 20, false, alg.NewMonitor(ctx, nil))
+						timedOut := ctx.Err() != nil
+						cancel()
+						if timedOut {
+							b.Skipf("timed out after %s", benchmarkTimeout)
+						}
+						visited, steps = nodes, bestSteps(paths)
+					}
+					b.ReportMetric(float64(visited), "nodes/op")
+					b.ReportMetric(float64(steps), "steps")
+				})
+			}
+		})
+	}
+}
+
+func TestSampleByTier(t *testing.T) {
+	elements := map[string]model.Element{
+		"Water": {Name: "Water"},
+		"Fire":  {Name: "Fire"},
+	}
+	for tier, count := range map[int]int{1: 2, 2: 6} {
+		for i := 0; i < count; i++ {
+			name := fmt.Sprintf("T%d-%d", tier, i)
+			elements[name] = model.Element{Name: name, Tier: tier}
+		}
+	}
+
+	// tier 1 diambil semua, tier 2 setiap elemen ketiga di urutan nama
+	want := []string{"T1-0", "T1-1", "T2-0", "T2-3"}
+	if sample := SampleByTier(elements, 2); !reflect.DeepEqual(sample, want) {
+		t.Fatalf("expected %v, got %v", want, sample)
+	}
+	if sample := SampleByTier(elements, 2); !reflect.DeepEqual(sample, want) {
+		t.Fatalf("sample must be stable, got %v", sample)
+	}
+}
+
+// TestMeasureKeepsFastestRun memastikan semua statistik Result berasal dari
+// run yang waktunya dicatat, bukan dari run terakhir
+func TestMeasureKeepsFastestRun(t *testing.T) {
+	calls := 0
+	alg.RegisterAlgorithm("bench-test", "slow first run with more paths", func(elements map[string]model.Element, target string, maxResults int, singlePath bool, monitor *alg.Monitor) ([][]model.Node, int) {
+		calls++
+		if calls == 2 {
+			return [][]model.Node{{{Element: "Water"}, {Element: "Fire"}, {Element: target}}}, 5
+		}
+		time.Sleep(20 * time.Millisecond)
+		return [][]model.Node{{{Element: target}}, {{Element: target}}}, 100
+	})
+	algorithm, _ := alg.LookupAlgorithm("bench-test")
+
+	elements := map[string]model.Element{"Steam": {Name: "Steam", Tier: 1}}
+	result := measure(elements, algorithm, "Steam", Config{Repeat: 3})
+
+	if result.TimeNs >= (20 * time.Millisecond).Nanoseconds() {
+		t.Fatalf("expected the fast second run to be kept, got %v", time.Duration(result.TimeNs))
+	}
+	if result.NodesVisited != 5 || result.Paths != 1 || result.BestSteps != 1 || !result.Found {
+		t.Fatalf("stats must come from the fast run, got %+v", result)
+	}
+}
@@ -0,0 +1,256 @@
+package bench
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// Report adalah hasil satu kali benchmark, disimpan sebagai JSON supaya bisa
+// jadi baseline untuk run berikutnya
+type Report struct {
+	DatasetVersion string    `json:"datasetVersion"`
+	CreatedAt      time.Time `json:"createdAt"`
+	GoVersion      string    `json:"goVersion"`
+	Platform       string    `json:"platform"`
+	NumCPU         int       `json:"numCPU"`
+	Config         Config    `json:"config"`
+	Summaries      []Summary `json:"summaries"`
+	Results        []Result  `json:"results"`
+}
+
+// Summary merangkum semua hasil satu algoritma
+type Summary struct {
+	Algorithm     string  `json:"algorithm"`
+	Runs          int     `json:"runs"`
+	Found         int     `json:"found"`
+	TimedOut      int     `json:"timedOut"`
+	TotalTimeNs   int64   `json:"totalTimeNs"`
+	MedianTimeNs  int64   `json:"medianTimeNs"`
+	P95TimeNs     int64   `json:"p95TimeNs"`
+	MeanAllocs    float64 `json:"meanAllocs"`
+	MeanBytes     float64 `json:"meanBytes"`
+	MeanVisited   float64 `json:"meanVisited"`
+	MeanBestSteps float64 `json:"meanBestSteps"`
+}
+
+// NewReport membuat report dari hasil Run
+func NewReport(datasetVersion string, config Config, results []Result) *Report {
+	return &Report{
+		DatasetVersion: datasetVersion,
+		CreatedAt:      time.Now(),
+		GoVersion:      runtime.Version(),
+		Platform:       runtime.GOOS + "/" + runtime.GOARCH,
+		NumCPU:         runtime.NumCPU(),
+		Config:         config,
+		Summaries:      Summarize(results),
+		Results:        results,
+	}
+}
+
+// Summarize mengelompokkan hasil per algoritma, urutannya mengikuti
+// kemunculan pertama algoritma di results
+func Summarize(results []Result) []Summary {
+	order := make([]string, 0)
+	byAlgorithm := make(map[string][]Result)
+	for _, result := range results {
+		if _, exists := byAlgorithm[result.Algorithm]; !exists {
+			order = append(order, result.Algorithm)
+		}
+		byAlgorithm[result.Algorithm] = append(byAlgorithm[result.Algorithm], result)
+	}
+
+	summaries := make([]Summary, 0, len(order))
+	for _, name := range order {
+		group := byAlgorithm[name]
+		summary := Summary{Algorithm: name, Runs: len(group)}
+
+		times := make([]int64, len(group))
+		stepsTotal, stepsCount := 0, 0
+		for i, result := range group {
+			times[i] = result.TimeNs
+			summary.TotalTimeNs += result.TimeNs
+			summary.MeanAllocs += float64(result.Allocs)
+			summary.MeanBytes += float64(result.AllocBytes)
+			summary.MeanVisited += float64(result.NodesVisited)
+			if result.Found {
+				summary.Found++
+				stepsTotal += result.BestSteps
+				stepsCount++
+			}
+			if result.TimedOut {
+				summary.TimedOut++
+			}
+		}
+
+		runs := float64(len(group))
+		summary.MeanAllocs /= runs
+		summary.MeanBytes /= runs
+		summary.MeanVisited /= runs
+		if stepsCount > 0 {
+			summary.MeanBestSteps = float64(stepsTotal) / float64(stepsCount)
+		}
+
+		sort.Slice(times, func(i, j int) bool { return times[i] < times[j] })
+		summary.MedianTimeNs = percentile(times, 0.5)
+		summary.P95TimeNs = percentile(times, 0.95)
+
+		summaries = append(summaries, summary)
+	}
+	return summaries
+}
+
+// percentile dari data yang sudah terurut, metode nearest-rank
+func percentile(sorted []int64, p float64) int64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := int(p*float64(len(sorted))+0.999999) - 1
+	if rank < 0 {
+		rank = 0
+	}
+	if rank >= len(sorted) {
+		rank = len(sorted) - 1
+	}
+	return sorted[rank]
+}
+
+// Comparison membandingkan satu algoritma dengan baseline, hanya di elemen
+// yang ada di kedua report supaya sampel yang berbeda tidak mengacaukan hasil
+type Comparison struct {
+	Algorithm      string  `json:"algorithm"`
+	Pairs          int     `json:"pairs"`
+	BaselineTimeNs int64   `json:"baselineTimeNs"`
+	CurrentTimeNs  int64   `json:"currentTimeNs"`
+	TimeChange     float64 `json:"timeChange"`
+	BaselineAllocs uint64  `json:"baselineAllocs"`
+	CurrentAllocs  uint64  `json:"currentAllocs"`
+	AllocChange    float64 `json:"allocChange"`
+	BaselineFound  int     `json:"baselineFound"`
+	CurrentFound   int     `json:"currentFound"`
+	// elemen yang resep terbaiknya sekarang butuh kombinasi lebih banyak
+	WorseSteps []string `json:"worseSteps,omitempty"`
+	Regression bool     `json:"regression"`
+	Reasons    []string `json:"reasons,omitempty"`
+}
+
+// Compare membandingkan current dengan baseline. Perubahan waktu atau
+// alokasi di atas threshold (0.1 = 10% lebih lambat), resep yang hilang, dan
+// resep yang jadi lebih panjang dianggap regresi.
+func Compare(baseline, current *Report, threshold float64) []Comparison {
+	baselineResults := make(map[string]Result, len(baseline.Results))
+	for _, result := range baseline.Results {
+		baselineResults[result.Key()] = result
+	}
+
+	comparisons := make([]Comparison, 0)
+	index := make(map[string]int)
+	for _, result := range current.Results {
+		old, exists := baselineResults[result.Key()]
+		if !exists {
+			continue
+		}
+
+		i, seen := index[result.Algorithm]
+		if !seen {
+			i = len(comparisons)
+			index[result.Algorithm] = i
+			comparisons = append(comparisons, Comparison{Algorithm: result.Algorithm})
+		}
+		comparison := &comparisons[i]
+
+		comparison.Pairs++
+		comparison.BaselineTimeNs += old.TimeNs
+		comparison.CurrentTimeNs += result.TimeNs
+		comparison.BaselineAllocs += old.Allocs
+		comparison.CurrentAllocs += result.Allocs
+		if old.Found {
+			comparison.BaselineFound++
+		}
+		if result.Found {
+			comparison.CurrentFound++
+		}
+		if old.Found && result.Found && result.BestSteps > old.BestSteps {
+			comparison.WorseSteps = append(comparison.WorseSteps, result.Element)
+		}
+	}
+
+	for i := range comparisons {
+		comparison := &comparisons[i]
+		comparison.TimeChange = change(float64(comparison.BaselineTimeNs), float64(comparison.CurrentTimeNs))
+		comparison.AllocChange = change(float64(comparison.BaselineAllocs), float64(comparison.CurrentAllocs))
+
+		if comparison.TimeChange > threshold {
+			comparison.Reasons = append(comparison.Reasons, fmt.Sprintf("%.1f%% slower", comparison.TimeChange*100))
+		}
+		if comparison.AllocChange > threshold {
+			comparison.Reasons = append(comparison.Reasons, fmt.Sprintf("%.1f%% more allocations", comparison.AllocChange*100))
+		}
+		if comparison.CurrentFound < comparison.BaselineFound {
+			comparison.Reasons = append(comparison.Reasons, fmt.Sprintf("%d fewer elements found", comparison.BaselineFound-comparison.CurrentFound))
+		}
+		if len(comparison.WorseSteps) > 0 {
+			comparison.Reasons = append(comparison.Reasons, fmt.Sprintf("longer recipes for %d elements", len(comparison.WorseSteps)))
+		}
+		comparison.Regression = len(comparison.Reasons) > 0
+	}
+	return comparisons
+}
+
+// change mengembalikan perubahan relatif, 0.25 berarti 25% lebih besar
+func change(before, after float64) float64 {
+	if before == 0 {
+		return 0
+	}
+	return after/before - 1
+}
+
+// LoadReport membaca report JSON yang disimpan dengan WriteJSON
+func LoadReport(path string) (*Report, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var report Report
+	if err := json.NewDecoder(file).Decode(&report); err != nil {
+		return nil, fmt.Errorf("failed to read report %s: %w", path, err)
+	}
+	return &report, nil
+}
+
+func WriteJSON(w io.Writer, report *Report) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(report)
+}
+
+// WriteCSV menulis satu baris per pasangan algoritma dan elemen
+func WriteCSV(w io.Writer, results []Result) error {
+	writer := csv.NewWriter(w)
+	writer.Write([]string{"algorithm", "element", "tier", "found", "paths", "bestSteps", "nodesVisited", "timeNs", "allocs", "allocBytes", "timedOut"})
+	for _, r := range results {
+		writer.Write([]string{
+			r.Algorithm,
+			r.Element,
+			strconv.Itoa(r.Tier),
+			strconv.FormatBool(r.Found),
+			strconv.Itoa(r.Paths),
+			strconv.Itoa(r.BestSteps),
+			strconv.Itoa(r.NodesVisited),
+			strconv.FormatInt(r.TimeNs, 10),
+			strconv.FormatUint(r.Allocs, 10),
+			strconv.FormatUint(r.AllocBytes, 10),
+			strconv.FormatBool(r.TimedOut),
+		})
+	}
+	writer.Flush()
+	return writer.Error()
+}
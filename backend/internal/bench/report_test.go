@@ -0,0 +1,83 @@
+package bench
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSummarize(t *testing.T) {
+	results := []Result{
+		{Algorithm: "bfs", Element: "Steam", Found: true, BestSteps: 1, NodesVisited: 10, TimeNs: 300, Allocs: 10},
+		{Algorithm: "dfs", Element: "Steam", Found: true, BestSteps: 2, NodesVisited: 4, TimeNs: 50},
+		{Algorithm: "bfs", Element: "Mud", Found: true, BestSteps: 3, NodesVisited: 20, TimeNs: 100, Allocs: 30},
+		{Algorithm: "bfs", Element: "Golem", BestSteps: -1, NodesVisited: 30, TimeNs: 200, Allocs: 20, TimedOut: true},
+	}
+
+	summaries := Summarize(results)
+	if len(summaries) != 2 || summaries[0].Algorithm != "bfs" || summaries[1].Algorithm != "dfs" {
+		t.Fatalf("expected bfs then dfs in order of first appearance, got %+v", summaries)
+	}
+
+	bfs := summaries[0]
+	want := Summary{
+		Algorithm:     "bfs",
+		Runs:          3,
+		Found:         2,
+		TimedOut:      1,
+		TotalTimeNs:   600,
+		MedianTimeNs:  200,
+		P95TimeNs:     300,
+		MeanAllocs:    20,
+		MeanVisited:   20,
+		MeanBestSteps: 2,
+	}
+	if bfs != want {
+		t.Fatalf("bfs summary\n got %+v\nwant %+v", bfs, want)
+	}
+	if dfs := summaries[1]; dfs.Runs != 1 || dfs.MedianTimeNs != 50 || dfs.P95TimeNs != 50 {
+		t.Fatalf("single run summary %+v", dfs)
+	}
+}
+
+func TestCompare(t *testing.T) {
+	baseline := &Report{Results: []Result{
+		{Algorithm: "bfs", Element: "Steam", Found: true, BestSteps: 1, TimeNs: 100, Allocs: 100},
+		{Algorithm: "bfs", Element: "Mud", Found: true, BestSteps: 2, TimeNs: 100, Allocs: 100},
+		{Algorithm: "bfs", Element: "Only in baseline", Found: true, TimeNs: 1000},
+		{Algorithm: "dfs", Element: "Steam", Found: true, BestSteps: 1, TimeNs: 100, Allocs: 100},
+	}}
+	current := &Report{Results: []Result{
+		{Algorithm: "bfs", Element: "Steam", Found: true, BestSteps: 1, TimeNs: 130, Allocs: 100},
+		{Algorithm: "bfs", Element: "Mud", Found: true, BestSteps: 4, TimeNs: 130, Allocs: 100},
+		{Algorithm: "dfs", Element: "Steam", Found: false, BestSteps: -1, TimeNs: 105, Allocs: 105},
+		{Algorithm: "dfs", Element: "Only in current", Found: true, TimeNs: 1000},
+	}}
+
+	comparisons := Compare(baseline, current, 0.1)
+	if len(comparisons) != 2 {
+		t.Fatalf("expected one comparison per algorithm, got %+v", comparisons)
+	}
+
+	bfs := comparisons[0]
+	if bfs.Algorithm != "bfs" || bfs.Pairs != 2 || bfs.BaselineTimeNs != 200 || bfs.CurrentTimeNs != 260 {
+		t.Fatalf("bfs should only compare elements in both reports, got %+v", bfs)
+	}
+	if !bfs.Regression || !reflect.DeepEqual(bfs.WorseSteps, []string{"Mud"}) {
+		t.Fatalf("bfs is 30%% slower with a longer Mud recipe, got %+v", bfs)
+	}
+	if !reflect.DeepEqual(bfs.Reasons, []string{"30.0% slower", "longer recipes for 1 elements"}) {
+		t.Fatalf("bfs reasons %q", bfs.Reasons)
+	}
+
+	dfs := comparisons[1]
+	if dfs.Pairs != 1 || dfs.BaselineFound != 1 || dfs.CurrentFound != 0 {
+		t.Fatalf("dfs comparison %+v", dfs)
+	}
+	if !reflect.DeepEqual(dfs.Reasons, []string{"1 fewer elements found"}) {
+		t.Fatalf("5%% slower is under the threshold, only the missing recipe counts, got %q", dfs.Reasons)
+	}
+
+	if comparisons := Compare(baseline, baseline, 0.1); comparisons[0].Regression || comparisons[1].Regression {
+		t.Fatalf("identical reports must not regress: %+v", comparisons)
+	}
+}
@@ -0,0 +1,264 @@
+// Package differential menjalankan beberapa algoritma pencarian untuk elemen
+// yang sama dan melaporkan setiap elemen di mana hasilnya tidak sepakat:
+// bisa dibuat atau tidak, kedalaman pohon resep terpendek, dan validitas
+// pohon. Jawaban yang benar dihitung langsung dari graf (ReachableFrom dan
+// ShortestDepths), jadi algoritma yang salah bisa langsung ditunjuk.
+package differential
+
+import (
+	alg "backend/internal/algorithm"
+	"backend/internal/graph"
+	"backend/model"
+	"backend/utils"
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+var baseElements = []string{"Water", "Fire", "Earth", "Air"}
+
+// jenis ketidaksepakatan
+const (
+	KindReachability = "reachability"
+	KindDepth        = "depth"
+	KindValidity     = "validity"
+)
+
+// DefaultAlgorithms adalah tiga implementasi yang dibandingkan kalo
+// Config.Algorithms kosong
+var DefaultAlgorithms = []string{"bfs", "dfs", "bidirectional"}
+
+// Config menentukan apa yang dibandingkan
+type Config struct {
+	Algorithms []string
+	// elemen yang dicek, kosong berarti semua elemen selain elemen dasar
+	Targets    []string
+	MaxResults int
+	// batas waktu satu pencarian, hasil yang timeout tidak ikut dibandingkan
+	Timeout time.Duration
+	// jumlah elemen yang dicek bersamaan
+	Concurrency int
+}
+
+// Outcome adalah hasil satu algoritma untuk satu elemen
+type Outcome struct {
+	Algorithm string `json:"algorithm"`
+	Found     bool   `json:"found"`
+	// kedalaman pohon valid paling dangkal, -1 kalo tidak ada
+	Depth        int      `json:"depth"`
+	Trees        int      `json:"trees"`
+	InvalidTrees int      `json:"invalidTrees,omitempty"`
+	Issues       []string `json:"issues,omitempty"`
+	TimedOut     bool     `json:"timedOut,omitempty"`
+	// pohon parsial untuk elemen yang tidak bisa dibuat. Node yang buntu
+	// sudah ditandai unmakeable, jadi pohon ini bukan klaim ada resep.
+	PartialTrees int `json:"partialTrees,omitempty"`
+}
+
+// Disagreement adalah satu elemen yang hasil algoritmanya tidak sepakat
+type Disagreement struct {
+	Element string `json:"element"`
+	Kind    string `json:"kind"`
+	// jawaban dari graf, Depth -1 kalo elemen tidak bisa dibuat
+	Reachable bool      `json:"reachable"`
+	Depth     int       `json:"depth"`
+	Outcomes  []Outcome `json:"outcomes"`
+	Detail    string    `json:"detail"`
+}
+
+// Report adalah hasil satu run
+type Report struct {
+	Algorithms    []string       `json:"algorithms"`
+	Elements      int            `json:"elements"`
+	TimedOut      int            `json:"timedOut"`
+	Disagreements []Disagreement `json:"disagreements"`
+}
+
+// Counts menghitung ketidaksepakatan per jenis
+func (r *Report) Counts() map[string]int {
+	counts := make(map[string]int)
+	for _, d := range r.Disagreements {
+		counts[d.Kind]++
+	}
+	return counts
+}
+
+// Run membandingkan algoritma untuk setiap target. progress dipanggil setelah
+// setiap elemen selesai, boleh nil.
+func Run(elements map[string]model.Element, config Config, progress func(done, total int, element string)) (*Report, error) {
+	if len(config.Algorithms) == 0 {
+		config.Algorithms = DefaultAlgorithms
+	}
+	algorithms := make([]alg.RegisteredAlgorithm, 0, len(config.Algorithms))
+	for _, name := range config.Algorithms {
+		algorithm, exists := alg.LookupAlgorithm(name)
+		if !exists {
+			return nil, fmt.Errorf("unknown algorithm %q", name)
+		}
+		algorithms = append(algorithms, algorithm)
+	}
+	if config.MaxResults <= 0 {
+		config.MaxResults = 20
+	}
+	if config.Concurrency <= 0 {
+		config.Concurrency = 1
+	}
+
+	targets := config.Targets
+	if len(targets) == 0 {
+		for name := range elements {
+			if !utils.IsBaseElementName(name, baseElements) {
+				targets = append(targets, name)
+			}
+		}
+		sort.Strings(targets)
+	}
+	for _, target := range targets {
+		if _, exists := elements[target]; !exists {
+			return nil, fmt.Errorf("element %q not found", target)
+		}
+	}
+
+	g := graph.NewElementGraph(elements)
+	reachable := g.ReachableFrom(baseElements)
+	depths := g.ShortestDepths(baseElements)
+
+	report := &Report{Algorithms: config.Algorithms, Elements: len(targets)}
+	var mu sync.Mutex
+	done := 0
+
+	jobs := make(chan string)
+	var wg sync.WaitGroup
+	for i := 0; i < config.Concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for target := range jobs {
+				outcomes := make([]Outcome, len(algorithms))
+				for j, algorithm := range algorithms {
+					outcomes[j] = run(elements, algorithm, target, reachable[target], config)
+				}
+				depth, isReachable := depths[target]
+				if !isReachable {
+					depth = -1
+				}
+				found := compare(target, reachable[target], depth, outcomes)
+
+				mu.Lock()
+				report.Disagreements = append(report.Disagreements, found...)
+				for _, outcome := range outcomes {
+					if outcome.TimedOut {
+						report.TimedOut++
+					}
+				}
+				done++
+				if progress != nil {
+					progress(done, len(targets), target)
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+	for _, target := range targets {
+		jobs <- target
+	}
+	close(jobs)
+	wg.Wait()
+
+	sort.Slice(report.Disagreements, func(i, j int) bool {
+		a, b := report.Disagreements[i], report.Disagreements[j]
+		if a.Element != b.Element {
+			return a.Element < b.Element
+		}
+		return a.Kind < b.Kind
+	})
+	return report, nil
+}
+
+func run(elements map[string]model.Element, algorithm alg.RegisteredAlgorithm, target string, reachable bool, config Config) Outcome {
+	ctx := context.Background()
+	cancel := func() {}
+	if config.Timeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, config.Timeout)
+	}
+	defer cancel()
+
+	paths, _ := algorithm.SearchWithMonitor(elements, target, config.MaxResults, false, alg.NewMonitor(ctx, nil))
+	outcome := Outcome{Algorithm: algorithm.Name, Depth: -1, TimedOut: ctx.Err() == context.DeadlineExceeded}
+
+	for _, tree := range utils.BuildTrees(elements, paths, target, 0) {
+		outcome.Trees++
+		if issues := utils.VerifyTree(tree, target, elements, baseElements); len(issues) > 0 {
+			if !reachable && !utils.IsTreeFullyMakeable(tree) {
+				outcome.PartialTrees++
+				continue
+			}
+			outcome.InvalidTrees++
+			outcome.Issues = append(outcome.Issues, issues[0].String())
+			continue
+		}
+		outcome.Found = true
+		if depth := treeDepth(tree); outcome.Depth < 0 || depth < outcome.Depth {
+			outcome.Depth = depth
+		}
+	}
+	return outcome
+}
+
+// compare mencari ketidaksepakatan satu elemen. Algoritma yang timeout
+// tidak dianggap salah karena hasilnya belum lengkap.
+func compare(element string, reachable bool, depth int, outcomes []Outcome) []Disagreement {
+	newDisagreement := func(kind, detail string) Disagreement {
+		return Disagreement{Element: element, Kind: kind, Reachable: reachable, Depth: depth, Outcomes: outcomes, Detail: detail}
+	}
+	var found []Disagreement
+	var missing, wrongDepth, invalid []string
+
+	for _, outcome := range outcomes {
+		if outcome.InvalidTrees > 0 {
+			invalid = append(invalid, fmt.Sprintf("%s returned %d invalid trees", outcome.Algorithm, outcome.InvalidTrees))
+		}
+		if outcome.TimedOut {
+			continue
+		}
+		if outcome.Found != reachable {
+			missing = append(missing, outcome.Algorithm)
+			continue
+		}
+		if outcome.Found && outcome.Depth != depth {
+			wrongDepth = append(wrongDepth, fmt.Sprintf("%s %d", outcome.Algorithm, outcome.Depth))
+		}
+	}
+
+	if len(missing) > 0 {
+		verb := "found no recipe"
+		if !reachable {
+			verb = "found a recipe for an unreachable element"
+		}
+		found = append(found, newDisagreement(KindReachability, strings.Join(missing, ", ")+" "+verb))
+	}
+	if len(wrongDepth) > 0 {
+		found = append(found, newDisagreement(KindDepth, fmt.Sprintf("shortest depth is %d, got %s", depth, strings.Join(wrongDepth, ", "))))
+	}
+	if len(invalid) > 0 {
+		found = append(found, newDisagreement(KindValidity, strings.Join(invalid, ", ")))
+	}
+	return found
+}
+
+// treeDepth menghitung tinggi pohon resep, elemen dasar 0
+func treeDepth(tree map[string]interface{}) int {
+	deepest := -1
+	children, _ := tree["ingredients"].([]interface{})
+	for _, child := range children {
+		if node, ok := child.(map[string]interface{}); ok {
+			if depth := treeDepth(node); depth > deepest {
+				deepest = depth
+			}
+		}
+	}
+	return deepest + 1
+}
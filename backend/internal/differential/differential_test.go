@@ -0,0 +1,192 @@
+package differential
+
+import (
+	"backend/internal"
+	"backend/internal/synthetic"
+	"backend/model"
+	"backend/utils"
+	"encoding/json"
+	"flag"
+	"io"
+	"log"
+	"os"
+	"runtime"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+)
+
+var (
+	update = flag.Bool("update", false, "tulis ulang testdata/known.json dengan ketidaksepakatan sekarang")
+	full   = flag.Bool("differential.full", false, "bandingkan juga di seluruh dataset (beberapa menit)")
+)
+
+// ketidaksepakatan yang sudah diketahui per graf. Test gagal kalo ada yang
+// baru, yang sudah hilang cukup dicatat supaya file ini bisa di-update.
+const knownPath = "testdata/known.json"
+
+// graf sintetis kecil supaya test cepat. Graf dengan siklus sengaja kecil,
+// BFS di graf bersiklus bisa meledak dan hasil yang timeout tidak dibandingkan.
+var syntheticGraphs = []struct {
+	name   string
+	config synthetic.Config
+}{
+	{"synthetic-1", synthetic.Config{Elements: 80, MinRecipes: 1, MaxRecipes: 3, FanIn: 2, Seed: 1}},
+	{"synthetic-2", synthetic.Config{Elements: 80, MinRecipes: 1, MaxRecipes: 3, FanIn: 2, Seed: 2}},
+	{"synthetic-wide", synthetic.Config{Elements: 120, Tiers: 5, MinRecipes: 2, MaxRecipes: 5, FanIn: 2, Seed: 3}},
+	{"synthetic-cycles", synthetic.Config{Elements: 25, MinRecipes: 1, MaxRecipes: 2, FanIn: 2, Cycles: 0.1, Seed: 5}},
+}
+
+func TestDifferential(t *testing.T) {
+	log.SetOutput(io.Discard)
+	defer log.SetOutput(os.Stderr)
+
+	known := loadKnown(t)
+	current := make(map[string][]string)
+
+	for _, graph := range syntheticGraphs {
+		generated, err := synthetic.Generate(graph.config)
+		if err != nil {
+			t.Fatal(err)
+		}
+		current[graph.name] = check(t, graph.name, synthetic.Map(generated), nil, known[graph.name])
+	}
+
+	dataset, err := internal.LoadDataset("../../testdata/elements.json", utils.DefaultValidationPolicy())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *full {
+		current["dataset"] = check(t, "dataset", dataset.Elements, nil, known["dataset"])
+	} else if entries := known["dataset"]; len(entries) > 0 {
+		// tanpa -differential.full cukup elemen yang tercatat yang dicek
+		// ulang, supaya entry yang sudah basi tetap ketahuan
+		current["dataset"] = check(t, "dataset", dataset.Elements, knownElements(entries), entries)
+	}
+
+	if *update {
+		data, _ := json.MarshalIndent(current, "", "  ")
+		if err := os.WriteFile(knownPath, append(data, '\n'), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// check menjalankan Run untuk satu graf (targets kosong berarti semua elemen)
+// dan mengembalikan ketidaksepakatan yang ditemukan dalam bentuk
+// "element: detail", detail menyebut algoritma yang tidak sepakat jadi
+// algoritma lain yang ikut salah tetap ketahuan
+func check(t *testing.T, name string, elements map[string]model.Element, targets, known []string) []string {
+	t.Helper()
+	report, err := Run(elements, Config{Targets: targets, Timeout: 10 * time.Second, Concurrency: runtime.NumCPU()}, nil)
+	if err != nil {
+		t.Fatalf("%s: %v", name, err)
+	}
+
+	knownSet := make(map[string]bool, len(known))
+	for _, entry := range known {
+		knownSet[entry] = true
+	}
+	entries := make([]string, 0, len(report.Disagreements))
+	for _, d := range report.Disagreements {
+		entry := d.Element + ": " + d.Detail
+		entries = append(entries, entry)
+		if knownSet[entry] {
+			delete(knownSet, entry)
+			continue
+		}
+		if !*update {
+			t.Errorf("%s: new %s disagreement for %s: %s", name, d.Kind, d.Element, d.Detail)
+		}
+	}
+
+	resolved := make([]string, 0, len(knownSet))
+	for entry := range knownSet {
+		resolved = append(resolved, entry)
+	}
+	sort.Strings(resolved)
+	for _, entry := range resolved {
+		t.Logf("%s: %q no longer disagrees, run with -update to remove it", name, entry)
+	}
+	t.Logf("%s: %d elements, %d disagreements %v, %d searches timed out", name, report.Elements, len(report.Disagreements), report.Counts(), report.TimedOut)
+	return entries
+}
+
+// knownElements mengambil nama elemen dari entry "element: detail"
+func knownElements(entries []string) []string {
+	seen := make(map[string]bool, len(entries))
+	elements := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		element, _, _ := strings.Cut(entry, ": ")
+		if !seen[element] {
+			seen[element] = true
+			elements = append(elements, element)
+		}
+	}
+	return elements
+}
+
+func loadKnown(t *testing.T) map[string][]string {
+	t.Helper()
+	known := make(map[string][]string)
+	data, err := os.ReadFile(knownPath)
+	if os.IsNotExist(err) {
+		return known
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(data, &known); err != nil {
+		t.Fatalf("invalid %s: %v", knownPath, err)
+	}
+	return known
+}
+
+func TestCompare(t *testing.T) {
+	outcome := func(algorithm string, found bool, depth, invalid int) Outcome {
+		return Outcome{Algorithm: algorithm, Found: found, Depth: depth, InvalidTrees: invalid}
+	}
+	tests := []struct {
+		name      string
+		reachable bool
+		depth     int
+		outcomes  []Outcome
+		want      []string
+	}{
+		{"agree", true, 3, []Outcome{outcome("bfs", true, 3, 0), outcome("dfs", true, 3, 0)}, nil},
+		{"agree unreachable", false, -1, []Outcome{outcome("bfs", false, -1, 0), outcome("dfs", false, -1, 0)}, nil},
+		{"missing", true, 3, []Outcome{outcome("bfs", true, 3, 0), outcome("dfs", false, -1, 0)}, []string{KindReachability}},
+		{"deeper", true, 3, []Outcome{outcome("bfs", true, 3, 0), outcome("dfs", true, 5, 0)}, []string{KindDepth}},
+		{"invalid", true, 3, []Outcome{outcome("bfs", true, 3, 1), outcome("dfs", true, 3, 0)}, []string{KindValidity}},
+		{"timeout ignored", true, 3, []Outcome{outcome("bfs", true, 3, 0), {Algorithm: "dfs", Depth: -1, TimedOut: true}}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			found := compare("X", tt.reachable, tt.depth, tt.outcomes)
+			kinds := make([]string, len(found))
+			for i, d := range found {
+				kinds[i] = d.Kind
+			}
+			if len(kinds) != len(tt.want) || (len(kinds) > 0 && kinds[0] != tt.want[0]) {
+				t.Fatalf("expected %v, got %v", tt.want, kinds)
+			}
+		})
+	}
+}
+
+func TestTreeDepth(t *testing.T) {
+	leaf := func(name string) interface{} {
+		return map[string]interface{}{"name": name, "ingredients": []interface{}{}}
+	}
+	steam := map[string]interface{}{"name": "Steam", "ingredients": []interface{}{leaf("Water"), leaf("Fire")}}
+	cloud := map[string]interface{}{"name": "Cloud", "ingredients": []interface{}{steam, leaf("Air")}}
+
+	if depth := treeDepth(leaf("Water").(map[string]interface{})); depth != 0 {
+		t.Fatalf("base element depth %d, expected 0", depth)
+	}
+	if depth := treeDepth(cloud); depth != 2 {
+		t.Fatalf("Cloud depth %d, expected 2", depth)
+	}
+}
@@ -2,6 +2,8 @@ package graph
 
 import (
 	"backend/model"
+	"sort"
+	"strings"
 )
 
 type Recipe struct {
@@ -37,7 +39,16 @@ func NewElementGraph(elements map[string]model.Element) *ElementGraph {
 		}
 	}
 
-	for name, element := range elements {
+	// urutan nama dibuat tetap supaya RecipesMakingOtherElements (dan hasil
+	// algoritma yang menelusurinya) sama setiap kali graf dibuat
+	names := make([]string, 0, len(elements))
+	for name := range elements {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		element := elements[name]
 		for _, recipe := range element.Recipes {
 			g.Nodes[name].RecipesToMakeThisElement = append(
 				g.Nodes[name].RecipesToMakeThisElement,
@@ -89,6 +100,11 @@ func (g *ElementGraph) GetPossibleCombinations(elem1, elem2 string) []string {
 	}
 
 	for _, recipe := range node1.RecipesMakingOtherElements {
+		// kombinasi selalu dua elemen, resep dengan jumlah ingredient lain
+		// (misalnya dari graf sintetis) tidak pernah cocok
+		if len(recipe.Ingredients) != 2 {
+			continue
+		}
 		if (recipe.Ingredients[0] == elem1 && recipe.Ingredients[1] == elem2) ||
 			(recipe.Ingredients[0] == elem2 && recipe.Ingredients[1] == elem1) {
 			results = append(results, recipe.Result)
@@ -97,3 +113,110 @@ func (g *ElementGraph) GetPossibleCombinations(elem1, elem2 string) []string {
 
 	return results
 }
+
+// ReachableFrom mengembalikan semua elemen yang bisa dibuat dari baseElements
+// dengan resep yang ada di graf.
+func (g *ElementGraph) ReachableFrom(baseElements []string) map[string]bool {
+	return g.ReachableWithout(baseElements, "")
+}
+
+// ReachableWithout sama seperti ReachableFrom tapi seolah-olah elemen removed
+// tidak ada di graf (tidak bisa dibuat dan tidak bisa dipakai sebagai ingredient).
+func (g *ElementGraph) ReachableWithout(baseElements []string, removed string) map[string]bool {
+	reachable := make(map[string]bool, len(g.Nodes))
+	for _, base := range baseElements {
+		if _, exists := g.Nodes[base]; exists && base != removed {
+			reachable[base] = true
+		}
+	}
+
+	changed := true
+	for changed {
+		changed = false
+		for name, node := range g.Nodes {
+			if reachable[name] || name == removed {
+				continue
+			}
+
+			for _, recipe := range node.RecipesToMakeThisElement {
+				if len(recipe.Ingredients) > 0 && allReachable(recipe.Ingredients, reachable) {
+					reachable[name] = true
+					changed = true
+					break
+				}
+			}
+		}
+	}
+
+	return reachable
+}
+
+func allReachable(ingredients []string, reachable map[string]bool) bool {
+	for _, ing := range ingredients {
+		if !reachable[ing] {
+			return false
+		}
+	}
+	return true
+}
+
+// ShortestDepths menghitung kedalaman pohon resep terpendek untuk setiap elemen
+// yang bisa dibuat: elemen dasar 0, selain itu 1 + kedalaman ingredient terdalam
+// pada resep terbaik. Elemen yang tidak bisa dibuat tidak ada di hasil.
+func (g *ElementGraph) ShortestDepths(baseElements []string) map[string]int {
+	depths := make(map[string]int, len(g.Nodes))
+	for _, base := range baseElements {
+		if _, exists := g.Nodes[base]; exists {
+			depths[base] = 0
+		}
+	}
+
+	changed := true
+	for changed {
+		changed = false
+		for name, node := range g.Nodes {
+			for _, recipe := range node.RecipesToMakeThisElement {
+				if len(recipe.Ingredients) == 0 {
+					continue
+				}
+
+				deepest, ok := 0, true
+				for _, ing := range recipe.Ingredients {
+					depth, exists := depths[ing]
+					if !exists {
+						ok = false
+						break
+					}
+					if depth > deepest {
+						deepest = depth
+					}
+				}
+
+				if !ok {
+					continue
+				}
+
+				if current, exists := depths[name]; !exists || deepest+1 < current {
+					depths[name] = deepest + 1
+					changed = true
+				}
+			}
+		}
+	}
+
+	return depths
+}
+
+// CountUses menghitung jumlah resep berbeda yang memakai elemen sebagai ingredient
+func (g *ElementGraph) CountUses(elementName string) int {
+	node := g.Nodes[elementName]
+	if node == nil {
+		return 0
+	}
+
+	seen := make(map[string]bool, len(node.RecipesMakingOtherElements))
+	for _, recipe := range node.RecipesMakingOtherElements {
+		seen[recipe.Result+":"+strings.Join(recipe.Ingredients, "+")] = true
+	}
+	return len(seen)
+}
@@ -0,0 +1,342 @@
+package jobs
+
+import (
+	alg "backend/internal/algorithm"
+	"backend/model"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+	"time"
+)
+
+type Status string
+
+const (
+	StatusQueued    Status = "queued"
+	StatusRunning   Status = "running"
+	StatusCompleted Status = "completed"
+	StatusFailed    Status = "failed"
+	StatusCancelled Status = "cancelled"
+)
+
+const (
+	DefaultConcurrency = 2
+	DefaultRetention   = 15 * time.Minute
+	// jumlah job yang boleh antre/jalan bersamaan, sisanya ditolak
+	MaxPendingJobs = 32
+	// jumlah job selesai yang disimpan, yang paling lama dibuang duluan
+	MaxStoredJobs = 200
+	// batas path sementara yang disimpan selama job jalan
+	maxPartialPaths = 500
+)
+
+var (
+	ErrUnknownAlgorithm = errors.New("unknown algorithm")
+	ErrQueueFull        = errors.New("too many pending jobs")
+)
+
+// Request adalah parameter pencarian satu job
+type Request struct {
+	Element    string `json:"element"`
+	Algorithm  string `json:"algorithm"`
+	MaxResults int    `json:"maxResults"`
+	SinglePath bool   `json:"singlePath"`
+	// jumlah pohon yang diminta client, MaxResults adalah jumlah path yang dicari
+	Count int `json:"count"`
+}
+
+// Snapshot adalah keadaan job pada satu waktu, aman dibaca tanpa lock
+type Snapshot struct {
+	ID         string
+	Request    Request
+	Status     Status
+	Error      string
+	Visited    int
+	PathsFound int
+	CreatedAt  time.Time
+	StartedAt  time.Time
+	FinishedAt time.Time
+	// path sementara selama job jalan, hasil akhir kalo sudah selesai
+	Paths [][]model.Node
+}
+
+type job struct {
+	id        string
+	request   Request
+	search    alg.RegisteredAlgorithm
+	ctx       context.Context
+	cancel    context.CancelFunc
+	monitor   *alg.Monitor
+	createdAt time.Time
+
+	mu         sync.Mutex
+	status     Status
+	err        string
+	partial    [][]model.Node
+	found      int
+	result     [][]model.Node
+	visited    int
+	startedAt  time.Time
+	finishedAt time.Time
+}
+
+// Manager menjalankan pencarian di background dengan jumlah job jalan yang
+// dibatasi, dan menyimpan hasilnya sampai retention lewat.
+type Manager struct {
+	elements  map[string]model.Element
+	slots     chan struct{}
+	retention time.Duration
+
+	mu   sync.Mutex
+	jobs map[string]*job
+}
+
+func NewManager(elements map[string]model.Element, concurrency int, retention time.Duration) *Manager {
+	if concurrency <= 0 {
+		concurrency = DefaultConcurrency
+	}
+	if retention <= 0 {
+		retention = DefaultRetention
+	}
+
+	return &Manager{
+		elements:  elements,
+		slots:     make(chan struct{}, concurrency),
+		retention: retention,
+		jobs:      make(map[string]*job),
+	}
+}
+
+// Submit mendaftarkan job baru dan langsung kembali, pencarian jalan di background
+func (m *Manager) Submit(req Request) (Snapshot, error) {
+	search, exists := alg.LookupAlgorithm(req.Algorithm)
+	if !exists {
+		return Snapshot{}, fmt.Errorf("%w: %q", ErrUnknownAlgorithm, req.Algorithm)
+	}
+
+	m.mu.Lock()
+	m.pruneLocked(time.Now())
+	pending := 0
+	for _, j := range m.jobs {
+		if !j.snapshot(false).finished() {
+			pending++
+		}
+	}
+	if pending >= MaxPendingJobs {
+		m.mu.Unlock()
+		return Snapshot{}, ErrQueueFull
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	j := &job{
+		id:        newJobID(),
+		request:   req,
+		search:    search,
+		ctx:       ctx,
+		cancel:    cancel,
+		createdAt: time.Now(),
+		status:    StatusQueued,
+	}
+	j.monitor = alg.NewMonitor(ctx, j.addPartial)
+	m.jobs[j.id] = j
+	m.mu.Unlock()
+
+	log.Printf("DEBUG: Job %s queued: %s for %s (max results %d)", j.id, req.Algorithm, req.Element, req.MaxResults)
+	go m.run(j)
+
+	return j.snapshot(false), nil
+}
+
+// Get mengembalikan keadaan job, withPaths false kalo path tidak dibutuhkan
+func (m *Manager) Get(id string, withPaths bool) (Snapshot, bool) {
+	m.mu.Lock()
+	m.pruneLocked(time.Now())
+	j, exists := m.jobs[id]
+	m.mu.Unlock()
+
+	if !exists {
+		return Snapshot{}, false
+	}
+	return j.snapshot(withPaths), true
+}
+
+// Cancel menghentikan job yang masih antre atau jalan. Job yang sudah selesai
+// tidak berubah.
+func (m *Manager) Cancel(id string) (Snapshot, bool) {
+	m.mu.Lock()
+	j, exists := m.jobs[id]
+	m.mu.Unlock()
+
+	if !exists {
+		return Snapshot{}, false
+	}
+
+	j.mu.Lock()
+	if j.status == StatusQueued || j.status == StatusRunning {
+		j.status = StatusCancelled
+		j.finishedAt = time.Now()
+		log.Printf("DEBUG: Job %s cancelled", j.id)
+	}
+	j.mu.Unlock()
+	j.cancel()
+
+	return j.snapshot(false), true
+}
+
+// List mengembalikan semua job yang masih disimpan, terbaru dulu
+func (m *Manager) List() []Snapshot {
+	m.mu.Lock()
+	m.pruneLocked(time.Now())
+	snapshots := make([]Snapshot, 0, len(m.jobs))
+	for _, j := range m.jobs {
+		snapshots = append(snapshots, j.snapshot(false))
+	}
+	m.mu.Unlock()
+
+	sort.Slice(snapshots, func(i, k int) bool {
+		return snapshots[i].CreatedAt.After(snapshots[k].CreatedAt)
+	})
+	return snapshots
+}
+
+func (m *Manager) run(j *job) {
+	select {
+	case m.slots <- struct{}{}:
+	case <-j.ctx.Done():
+		return
+	}
+	defer func() { <-m.slots }()
+
+	j.mu.Lock()
+	if j.status != StatusQueued {
+		j.mu.Unlock()
+		return
+	}
+	j.status = StatusRunning
+	j.startedAt = time.Now()
+	j.mu.Unlock()
+
+	var paths [][]model.Node
+	var visited int
+	var runErr error
+	func() {
+		defer func() {
+			if r := recover(); r != nil {
+				log.Printf("ERROR: Job %s panicked: %v", j.id, r)
+				runErr = fmt.Errorf("search failed: %v", r)
+			}
+		}()
+		paths, visited = j.search.SearchWithMonitor(m.elements, j.request.Element, j.request.MaxResults, j.request.SinglePath, j.monitor)
+	}()
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	j.visited = visited
+	if j.status == StatusCancelled {
+		// path sementara tetap disimpan sebagai hasil parsial
+		return
+	}
+
+	j.finishedAt = time.Now()
+	if runErr != nil {
+		j.status = StatusFailed
+		j.err = runErr.Error()
+		return
+	}
+
+	j.status = StatusCompleted
+	j.result = paths
+	j.partial = nil
+	log.Printf("DEBUG: Job %s completed with %d paths after visiting %d nodes in %v",
+		j.id, len(paths), visited, j.finishedAt.Sub(j.startedAt))
+}
+
+// pruneLocked membuang job selesai yang sudah lewat retention, dan job selesai
+// paling lama kalo jumlahnya lebih dari MaxStoredJobs. m.mu harus sudah dikunci.
+func (m *Manager) pruneLocked(now time.Time) {
+	finished := make([]Snapshot, 0)
+	for id, j := range m.jobs {
+		snapshot := j.snapshot(false)
+		if !snapshot.finished() {
+			continue
+		}
+		if now.Sub(snapshot.FinishedAt) > m.retention {
+			delete(m.jobs, id)
+			continue
+		}
+		finished = append(finished, snapshot)
+	}
+
+	if len(finished) <= MaxStoredJobs {
+		return
+	}
+	sort.Slice(finished, func(i, k int) bool {
+		return finished[i].FinishedAt.Before(finished[k].FinishedAt)
+	})
+	for _, snapshot := range finished[:len(finished)-MaxStoredJobs] {
+		delete(m.jobs, snapshot.ID)
+	}
+}
+
+func (j *job) addPartial(path []model.Node) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	j.found++
+	if len(j.partial) < maxPartialPaths {
+		j.partial = append(j.partial, path)
+	}
+}
+
+func (j *job) snapshot(withPaths bool) Snapshot {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	snapshot := Snapshot{
+		ID:         j.id,
+		Request:    j.request,
+		Status:     j.status,
+		Error:      j.err,
+		Visited:    j.visited,
+		PathsFound: j.found,
+		CreatedAt:  j.createdAt,
+		StartedAt:  j.startedAt,
+		FinishedAt: j.finishedAt,
+	}
+
+	if j.status == StatusRunning {
+		snapshot.Visited = j.monitor.Visited()
+	}
+	if j.status == StatusCompleted {
+		snapshot.PathsFound = len(j.result)
+	}
+
+	if withPaths {
+		source := j.partial
+		if j.status == StatusCompleted {
+			source = j.result
+		}
+		snapshot.Paths = make([][]model.Node, len(source))
+		copy(snapshot.Paths, source)
+	}
+
+	return snapshot
+}
+
+func (s Snapshot) finished() bool {
+	return s.Status == StatusCompleted || s.Status == StatusFailed || s.Status == StatusCancelled
+}
+
+func newJobID() string {
+	buf := make([]byte, 8)
+	if _, err := rand.Read(buf); err != nil {
+		return fmt.Sprintf("%x", time.Now().UnixNano())
+	}
+	return hex.EncodeToString(buf)
+}
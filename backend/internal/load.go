@@ -4,26 +4,72 @@ import (
 	"backend/internal/graph"
 	"backend/model"
 	"backend/utils"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
+	"io/fs"
+	"log"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
 )
 
+// Dataset adalah hasil load satu file elemen beserta policy validasinya.
+type Dataset struct {
+	Path     string
+	Elements map[string]model.Element
+	Graph    *graph.ElementGraph
+	Policy   utils.ValidationPolicy
+	Report   utils.ValidationReport
+	// hash isi dataset setelah validasi, berubah kalo elemen, resep atau policy berubah
+	Version string
+}
+
 func LoadElements() (map[string]model.Element, *graph.ElementGraph, error) {
-	absPath, err := filepath.Abs("elements.json")
+	policy, err := utils.PolicyFromEnv()
 	if err != nil {
 		return nil, nil, err
 	}
 
-	file, err := os.Open(absPath)
+	dataset, err := LoadDataset("elements.json", policy)
 	if err != nil {
 		return nil, nil, err
 	}
+
+	return dataset.Elements, dataset.Graph, nil
+}
+
+// LoadDataset membaca file elemen di path dan memvalidasi resepnya.
+// Kalo ada file policy di samping dataset (elements.policy.json untuk
+// elements.json), isinya menimpa policy yang dikasih, kecuali
+// excludedElements yang digabung dengan daftar yang sudah ada.
+func LoadDataset(path string, policy utils.ValidationPolicy) (*Dataset, error) {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+
+	policyPath := PolicyPathFor(absPath)
+	policy, err = utils.LoadValidationPolicy(policyPath, policy)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+	if err == nil {
+		log.Printf("Using validation policy from %s (excluded elements merged with RECIPE_EXCLUDE: %v)",
+			policyPath, policy.ExcludedElements)
+	}
+
+	file, err := os.Open(absPath)
+	if err != nil {
+		return nil, err
+	}
 	defer file.Close()
 
 	var elementsList []model.Element
 	if err := json.NewDecoder(file).Decode(&elementsList); err != nil {
-		return nil, nil, err
+		return nil, err
 	}
 
 	//buat convert list ke map biar efisien pas lookup
@@ -33,10 +79,39 @@ func LoadElements() (map[string]model.Element, *graph.ElementGraph, error) {
 	}
 
 	//ini buat validasi tier, di spek blg ga boleh kek misal
-	elementsMap = utils.ValidateRecipeTiers(elementsMap)
+	elementsMap, report := utils.ValidateRecipes(elementsMap, policy)
 
 	//buat graf
 	elementGraph := graph.NewElementGraph(elementsMap)
 
-	return elementsMap, elementGraph, nil
+	return &Dataset{
+		Path:     absPath,
+		Elements: elementsMap,
+		Graph:    elementGraph,
+		Policy:   policy,
+		Report:   report,
+		Version:  DatasetVersion(elementsMap),
+	}, nil
+}
+
+// DatasetVersion menghitung hash pendek dari elemen, urutannya tidak berpengaruh
+func DatasetVersion(elements map[string]model.Element) string {
+	names := make([]string, 0, len(elements))
+	for name := range elements {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	hash := sha256.New()
+	encoder := json.NewEncoder(hash)
+	for _, name := range names {
+		// error dari encoder cuma mungkin kalo tipe tidak bisa di-encode
+		encoder.Encode(elements[name])
+	}
+	return hex.EncodeToString(hash.Sum(nil))[:12]
+}
+
+// PolicyPathFor mengembalikan lokasi file policy untuk sebuah dataset.
+func PolicyPathFor(datasetPath string) string {
+	return strings.TrimSuffix(datasetPath, filepath.Ext(datasetPath)) + ".policy.json"
 }
@@ -0,0 +1,311 @@
+package recordings
+
+import (
+	alg "backend/internal/algorithm"
+	"backend/model"
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// versi format file, naikkan kalo isi baris berubah
+const FormatVersion = 1
+
+const (
+	DefaultDir = "recordings"
+	fileExt    = ".jsonl"
+)
+
+var (
+	ErrNotFound  = errors.New("recording not found")
+	ErrInvalidID = errors.New("invalid recording id")
+)
+
+// Header adalah baris pertama file rekaman, cukup untuk ditampilkan di daftar
+// tanpa membaca semua event
+type Header struct {
+	Format         int       `json:"format"`
+	Element        string    `json:"element"`
+	Algorithm      string    `json:"algorithm"`
+	DatasetVersion string    `json:"datasetVersion"`
+	CreatedAt      time.Time `json:"createdAt"`
+	Steps          int       `json:"steps"`
+	Truncated      bool      `json:"truncated"`
+	NodesVisited   int       `json:"nodesVisited"`
+	TimeElapsed    int64     `json:"timeElapsed"`
+}
+
+// Recording adalah semua event satu pencarian beserta hasil akhirnya
+type Recording struct {
+	Header
+	Events []alg.SearchEvent
+	Paths  [][]model.Node
+}
+
+// Info adalah ringkasan rekaman di daftar
+type Info struct {
+	ID string `json:"id"`
+	Header
+	Size int64 `json:"size"`
+}
+
+// line adalah satu baris file: header dulu, lalu event satu per baris, lalu
+// hasil pencarian
+type line struct {
+	Type   string           `json:"type"`
+	Header *Header          `json:"header,omitempty"`
+	Event  *alg.SearchEvent `json:"event,omitempty"`
+	Paths  [][]model.Node   `json:"paths,omitempty"`
+}
+
+// Store menyimpan rekaman sebagai file JSON lines di satu folder. Rekaman
+// untuk elemen, algoritma dan versi dataset yang sama ditimpa yang terbaru.
+type Store struct {
+	dir string
+}
+
+func NewStore(dir string) *Store {
+	if dir == "" {
+		dir = DefaultDir
+	}
+	return &Store{dir: dir}
+}
+
+// DirFromEnv membaca folder rekaman dari RECORDINGS_DIR
+func DirFromEnv() string {
+	if dir := os.Getenv("RECORDINGS_DIR"); dir != "" {
+		return dir
+	}
+	return DefaultDir
+}
+
+// ID mengembalikan id rekaman untuk elemen, algoritma dan versi dataset.
+// Slug bisa sama untuk nama yang berbeda ("Big Bang" dan "Big-Bang") atau
+// kosong untuk nama non-ASCII, jadi id diakhiri hash pendek dari nama aslinya
+// supaya rekaman tidak saling menimpa.
+func ID(element, algorithm, datasetVersion string) string {
+	sum := sha256.Sum256([]byte(datasetVersion + "\x00" + algorithm + "\x00" + element))
+	elementSlug := slug(element)
+	if elementSlug != "" {
+		elementSlug += "-"
+	}
+	return slug(datasetVersion) + "_" + slug(algorithm) + "_" + elementSlug + hex.EncodeToString(sum[:4])
+}
+
+// Save menulis rekaman, file lama dengan id yang sama ditimpa
+func (s *Store) Save(rec *Recording) (string, error) {
+	if err := os.MkdirAll(s.dir, 0o755); err != nil {
+		return "", err
+	}
+
+	rec.Format = FormatVersion
+	rec.Steps = len(rec.Events)
+	if rec.CreatedAt.IsZero() {
+		rec.CreatedAt = time.Now()
+	}
+
+	id := ID(rec.Element, rec.Algorithm, rec.DatasetVersion)
+	// tulis ke file sementara dulu supaya rekaman yang sedang dibaca tidak rusak
+	tmp, err := os.CreateTemp(s.dir, id+"-*.tmp")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(tmp.Name())
+
+	writer := bufio.NewWriter(tmp)
+	encoder := json.NewEncoder(writer)
+	if err := encoder.Encode(line{Type: "header", Header: &rec.Header}); err != nil {
+		tmp.Close()
+		return "", err
+	}
+	for i := range rec.Events {
+		if err := encoder.Encode(line{Type: "event", Event: &rec.Events[i]}); err != nil {
+			tmp.Close()
+			return "", err
+		}
+	}
+	if err := encoder.Encode(line{Type: "result", Paths: rec.Paths}); err != nil {
+		tmp.Close()
+		return "", err
+	}
+	if err := writer.Flush(); err != nil {
+		tmp.Close()
+		return "", err
+	}
+	if err := tmp.Close(); err != nil {
+		return "", err
+	}
+
+	if err := os.Rename(tmp.Name(), filepath.Join(s.dir, id+fileExt)); err != nil {
+		return "", err
+	}
+	return id, nil
+}
+
+// Load membaca satu rekaman lengkap
+func (s *Store) Load(id string) (*Recording, error) {
+	file, err := s.open(id)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	rec := &Recording{}
+	scanner := newScanner(file)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		var l line
+		if err := json.Unmarshal(scanner.Bytes(), &l); err != nil {
+			return nil, fmt.Errorf("recording %s line %d: %w", id, lineNumber, err)
+		}
+
+		switch {
+		case l.Type == "header" && l.Header != nil:
+			rec.Header = *l.Header
+		case l.Type == "event" && l.Event != nil:
+			rec.Events = append(rec.Events, *l.Event)
+		case l.Type == "result":
+			rec.Paths = l.Paths
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	if rec.Format == 0 {
+		return nil, fmt.Errorf("recording %s has no header", id)
+	}
+	if rec.Format > FormatVersion {
+		return nil, fmt.Errorf("recording %s uses format %d, only %d is supported", id, rec.Format, FormatVersion)
+	}
+	return rec, nil
+}
+
+// Path mengembalikan lokasi file rekaman, dipakai untuk download
+func (s *Store) Path(id string) (string, error) {
+	if !validID(id) {
+		return "", ErrInvalidID
+	}
+	path := filepath.Join(s.dir, id+fileExt)
+	if _, err := os.Stat(path); errors.Is(err, fs.ErrNotExist) {
+		return "", ErrNotFound
+	} else if err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+// List mengembalikan semua rekaman, terbaru dulu. File yang rusak dilewati.
+func (s *Store) List() ([]Info, error) {
+	entries, err := os.ReadDir(s.dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return []Info{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	infos := make([]Info, 0, len(entries))
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), fileExt) {
+			continue
+		}
+
+		id := strings.TrimSuffix(entry.Name(), fileExt)
+		header, err := s.readHeader(id)
+		if err != nil {
+			continue
+		}
+		info := Info{ID: id, Header: header}
+		if stat, err := entry.Info(); err == nil {
+			info.Size = stat.Size()
+		}
+		infos = append(infos, info)
+	}
+
+	sort.Slice(infos, func(i, j int) bool {
+		return infos[i].CreatedAt.After(infos[j].CreatedAt)
+	})
+	return infos, nil
+}
+
+func (s *Store) readHeader(id string) (Header, error) {
+	file, err := s.open(id)
+	if err != nil {
+		return Header{}, err
+	}
+	defer file.Close()
+
+	scanner := newScanner(file)
+	if !scanner.Scan() {
+		if err := scanner.Err(); err != nil {
+			return Header{}, err
+		}
+		return Header{}, fmt.Errorf("recording %s is empty", id)
+	}
+
+	var l line
+	if err := json.Unmarshal(scanner.Bytes(), &l); err != nil {
+		return Header{}, err
+	}
+	if l.Type != "header" || l.Header == nil {
+		return Header{}, fmt.Errorf("recording %s has no header", id)
+	}
+	return *l.Header, nil
+}
+
+func (s *Store) open(id string) (*os.File, error) {
+	if !validID(id) {
+		return nil, ErrInvalidID
+	}
+	file, err := os.Open(filepath.Join(s.dir, id+fileExt))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, ErrNotFound
+	}
+	return file, err
+}
+
+func newScanner(file *os.File) *bufio.Scanner {
+	scanner := bufio.NewScanner(file)
+	// baris hasil bisa panjang kalo path-nya banyak
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	return scanner
+}
+
+// slug mengubah nama jadi aman untuk nama file, contoh "Big Bang" jadi "big-bang"
+func slug(name string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(name) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			b.WriteRune(r)
+			dash = false
+		} else if !dash && b.Len() > 0 {
+			b.WriteByte('-')
+			dash = true
+		}
+	}
+	return strings.TrimSuffix(b.String(), "-")
+}
+
+// validID memastikan id hanya berisi karakter dari slug, supaya tidak bisa
+// dipakai untuk membaca file di luar folder rekaman
+func validID(id string) bool {
+	if id == "" {
+		return false
+	}
+	for _, r := range id {
+		if !((r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') || r == '-' || r == '_') {
+			return false
+		}
+	}
+	return true
+}
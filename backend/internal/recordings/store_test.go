@@ -0,0 +1,25 @@
+package recordings
+
+import "testing"
+
+func TestIDIsUniquePerName(t *testing.T) {
+	names := []string{"Big Bang", "Big-Bang", "big bang", "Big  Bang", "日本", "中国", "Éclair", "clair", ""}
+	seen := make(map[string]string, len(names))
+	for _, name := range names {
+		id := ID(name, "bfs", "v1")
+		if !validID(id) {
+			t.Errorf("ID(%q) = %q is not a valid id", name, id)
+		}
+		if other, exists := seen[id]; exists {
+			t.Errorf("ID(%q) and ID(%q) are both %q", name, other, id)
+		}
+		seen[id] = name
+	}
+
+	if ID("Big Bang", "bfs", "v1") != ID("Big Bang", "bfs", "v1") {
+		t.Fatal("ID must be stable for the same input")
+	}
+	if ID("Steam", "bfs", "v1") == ID("Steam", "dfs", "v1") || ID("Steam", "bfs", "v1") == ID("Steam", "bfs", "v2") {
+		t.Fatal("ID must differ per algorithm and dataset version")
+	}
+}
@@ -0,0 +1,193 @@
+// Package synthetic membuat graf resep acak dengan bentuk seperti
+// elements.json, supaya algoritma bisa dites di graf kecil yang mudah
+// dibaca dan di-stress test di graf yang jauh lebih besar dari dataset asli.
+//
+// Tier selalu konsisten: setiap resep biasa hanya memakai ingredient dari
+// tier yang lebih kecil, dan paling tidak satu ingredient dari tier tepat di
+// bawahnya, jadi tier elemen = 1 + tier ingredient tertinggi. Resep "siklus" (Config.Cycles)
+// sengaja melanggar aturan itu, jadi dibuang oleh policy tier-order kecuali
+// dataset di-load dengan RECIPE_POLICIES=none.
+package synthetic
+
+import (
+	"backend/model"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"math/rand"
+	"sort"
+	"strings"
+)
+
+// BaseElements sama dengan elemen dasar dataset asli
+var BaseElements = []string{"Water", "Fire", "Earth", "Air"}
+
+// Config menentukan bentuk graf yang dibuat
+type Config struct {
+	// jumlah elemen termasuk 4 elemen dasar
+	Elements int `json:"elements"`
+	// jumlah tier di atas elemen dasar, 0 berarti dipilih dari jumlah elemen
+	Tiers int `json:"tiers"`
+	// jumlah resep per elemen bukan dasar, dipilih acak di antara min dan max
+	MinRecipes int `json:"minRecipes"`
+	MaxRecipes int `json:"maxRecipes"`
+	// fan-in, jumlah ingredient per resep. Untuk sekarang harus 2 seperti
+	// dataset asli: bidirectional dan combine hanya mengenal resep dua
+	// ingredient
+	FanIn int `json:"fanIn"`
+	// peluang sebuah elemen dapat satu resep tambahan yang memakai ingredient
+	// dari tier yang sama atau lebih tinggi, 0 berarti graf tanpa siklus
+	Cycles float64 `json:"cycles"`
+	Seed   int64   `json:"seed"`
+}
+
+// DefaultConfig mirip dataset asli: sekitar 700 elemen, 15 tier, 2 ingredient
+// per resep
+func DefaultConfig() Config {
+	return Config{
+		Elements:   700,
+		MinRecipes: 1,
+		MaxRecipes: 4,
+		FanIn:      2,
+		Seed:       1,
+	}
+}
+
+func (c Config) validate() error {
+	switch {
+	case c.Elements <= len(BaseElements):
+		return fmt.Errorf("elements must be more than %d (the base elements), got %d", len(BaseElements), c.Elements)
+	case c.Tiers < 0:
+		return fmt.Errorf("tiers must not be negative, got %d", c.Tiers)
+	case c.Tiers > c.Elements-len(BaseElements):
+		return fmt.Errorf("tiers (%d) must not exceed the number of non-base elements (%d)", c.Tiers, c.Elements-len(BaseElements))
+	case c.MinRecipes < 1 || c.MaxRecipes < c.MinRecipes:
+		return fmt.Errorf("recipes per element must satisfy 1 <= min <= max, got %d..%d", c.MinRecipes, c.MaxRecipes)
+	case c.FanIn != 2:
+		return fmt.Errorf("fan-in must be 2, the search algorithms only handle two-ingredient recipes, got %d", c.FanIn)
+	case c.Cycles < 0 || c.Cycles > 1:
+		return fmt.Errorf("cycles must be between 0 and 1, got %g", c.Cycles)
+	}
+	return nil
+}
+
+// tiers mengembalikan jumlah tier yang dipakai, defaultnya tumbuh
+// logaritmik dengan jumlah elemen (700 elemen -> 15 tier seperti dataset asli)
+func (c Config) tiers() int {
+	if c.Tiers > 0 {
+		return c.Tiers
+	}
+	tiers := int(math.Round(2.3 * math.Log(float64(c.Elements))))
+	if limit := c.Elements - len(BaseElements); tiers > limit {
+		tiers = limit
+	}
+	return tiers
+}
+
+// Generate membuat graf resep, hasilnya selalu sama untuk Config yang sama.
+// Urutan elemen: elemen dasar dulu, lalu per tier.
+func Generate(config Config) ([]model.Element, error) {
+	if err := config.validate(); err != nil {
+		return nil, err
+	}
+	rng := rand.New(rand.NewSource(config.Seed))
+	tierCount := config.tiers()
+
+	// byTier[t] berisi nama elemen di tier t, tier 0 adalah elemen dasar
+	byTier := make([][]string, tierCount+1)
+	byTier[0] = append([]string{}, BaseElements...)
+	remaining := config.Elements - len(BaseElements)
+	width := len(fmt.Sprint(remaining))
+	for i := 0; i < remaining; i++ {
+		// setiap tier dapat jumlah elemen yang (hampir) sama
+		tier := 1 + i*tierCount/remaining
+		byTier[tier] = append(byTier[tier], fmt.Sprintf("Element %0*d", width, i+1))
+	}
+
+	elements := make([]model.Element, 0, config.Elements)
+	// below berisi semua elemen dari tier sebelumnya, tempat memilih ingredient
+	below := make([]string, 0, config.Elements)
+	for tier, names := range byTier {
+		for _, name := range names {
+			element := model.Element{Name: name, Tier: tier}
+			if tier > 0 {
+				element.Recipes = recipes(rng, config, below, byTier[tier-1])
+			}
+			elements = append(elements, element)
+		}
+		below = append(below, names...)
+	}
+
+	if config.Cycles > 0 {
+		addCycles(rng, config, elements)
+	}
+	return elements, nil
+}
+
+// recipes membuat resep biasa untuk satu elemen. Setiap resep memakai satu
+// ingredient dari tier tepat di bawah, kalo tidak resep itu membuat elemen
+// bisa dibuat lebih awal dan tier-nya jadi tidak konsisten.
+func recipes(rng *rand.Rand, config Config, below, previousTier []string) []model.ElementRecipe {
+	count := config.MinRecipes + rng.Intn(config.MaxRecipes-config.MinRecipes+1)
+	result := make([]model.ElementRecipe, 0, count)
+	seen := make(map[string]bool, count)
+
+	// kombinasi di tier rendah bisa habis, jadi percobaan dibatasi
+	for attempt := 0; len(result) < count && attempt < count*8; attempt++ {
+		ingredients := make([]string, config.FanIn)
+		for i := range ingredients {
+			ingredients[i] = below[rng.Intn(len(below))]
+		}
+		ingredients[0] = previousTier[rng.Intn(len(previousTier))]
+		sort.Strings(ingredients)
+
+		key := strings.Join(ingredients, "+")
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		result = append(result, model.ElementRecipe{Ingredients: ingredients})
+	}
+	return result
+}
+
+// addCycles menambahkan resep yang memakai elemen dari tier yang sama atau
+// lebih tinggi, seperti Water = Heat + Ice di dataset asli
+func addCycles(rng *rand.Rand, config Config, elements []model.Element) {
+	// elemen terakhir tidak punya elemen lain di tier yang sama atau lebih tinggi
+	for i := 0; i < len(elements)-1; i++ {
+		if rng.Float64() >= config.Cycles {
+			continue
+		}
+		// elements terurut per tier, jadi elements[i+1:] bertier >= elemen ini
+		ingredients := make([]string, config.FanIn)
+		for j := range ingredients {
+			// elemen ini sendiri dilewati, resep X = X + Y tidak ada gunanya
+			k := rng.Intn(len(elements) - 1)
+			if k >= i {
+				k++
+			}
+			ingredients[j] = elements[k].Name
+		}
+		ingredients[0] = elements[i+1+rng.Intn(len(elements)-i-1)].Name
+		sort.Strings(ingredients)
+		elements[i].Recipes = append(elements[i].Recipes, model.ElementRecipe{Ingredients: ingredients})
+	}
+}
+
+// Map mengubah hasil Generate jadi map seperti yang dipakai algoritma
+func Map(elements []model.Element) map[string]model.Element {
+	result := make(map[string]model.Element, len(elements))
+	for _, element := range elements {
+		result[element.Name] = element
+	}
+	return result
+}
+
+// Write menulis elemen dengan format yang sama dengan elements.json
+func Write(w io.Writer, elements []model.Element) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(elements)
+}
@@ -0,0 +1,194 @@
+package synthetic
+
+import (
+	"backend/internal"
+	"backend/internal/graph"
+	"backend/model"
+	"backend/utils"
+	"bytes"
+	"encoding/json"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func generate(t *testing.T, config Config) map[string]model.Element {
+	t.Helper()
+	elements, err := Generate(config)
+	if err != nil {
+		t.Fatalf("Generate(%+v): %v", config, err)
+	}
+	return Map(elements)
+}
+
+func TestGenerateIsDeterministic(t *testing.T) {
+	config := DefaultConfig()
+	config.Cycles = 0.1
+	first, _ := Generate(config)
+	second, _ := Generate(config)
+	if !reflect.DeepEqual(first, second) {
+		t.Fatal("same config produced different graphs")
+	}
+
+	config.Seed++
+	third, _ := Generate(config)
+	if reflect.DeepEqual(first, third) {
+		t.Fatal("different seeds produced the same graph")
+	}
+}
+
+func TestGenerateShape(t *testing.T) {
+	config := Config{Elements: 200, Tiers: 8, MinRecipes: 2, MaxRecipes: 3, FanIn: 2, Seed: 5}
+	elements := generate(t, config)
+
+	if len(elements) != config.Elements {
+		t.Fatalf("expected %d elements, got %d", config.Elements, len(elements))
+	}
+	tiers := make(map[int]int)
+	for name, element := range elements {
+		tiers[element.Tier]++
+		if utils.IsBaseElementName(name, BaseElements) {
+			if element.Tier != 0 || len(element.Recipes) != 0 {
+				t.Errorf("base element %s has tier %d and %d recipes", name, element.Tier, len(element.Recipes))
+			}
+			continue
+		}
+		if len(element.Recipes) < 1 || len(element.Recipes) > config.MaxRecipes {
+			t.Errorf("%s has %d recipes, expected 1..%d", name, len(element.Recipes), config.MaxRecipes)
+		}
+		for _, recipe := range element.Recipes {
+			if len(recipe.Ingredients) != config.FanIn {
+				t.Errorf("%s has recipe %v, expected %d ingredients", name, recipe.Ingredients, config.FanIn)
+			}
+		}
+	}
+	if len(tiers) != config.Tiers+1 {
+		t.Errorf("expected %d tiers plus the base tier, got %v", config.Tiers, tiers)
+	}
+}
+
+// tanpa siklus, semua resep lolos policy tier-order dan semua elemen bisa
+// dibuat dari elemen dasar
+func TestGenerateTierConsistent(t *testing.T) {
+	log.SetOutput(io.Discard)
+	defer log.SetOutput(os.Stderr)
+
+	for _, seed := range []int64{1, 2, 3} {
+		config := DefaultConfig()
+		config.Seed = seed
+		elements := generate(t, config)
+
+		_, report := utils.ValidateRecipes(elements, utils.DefaultValidationPolicy())
+		if len(report.Rejected) > 0 {
+			t.Fatalf("seed %d: %d recipes break tier order, first %+v", seed, len(report.Rejected), report.Rejected[0])
+		}
+
+		for name, element := range elements {
+			if len(element.Recipes) == 0 {
+				continue
+			}
+			// tier elemen = 1 + tier ingredient tertinggi di resep termudah
+			lowest := -1
+			for _, recipe := range element.Recipes {
+				highest := 0
+				for _, ingredient := range recipe.Ingredients {
+					if tier := elements[ingredient].Tier; tier > highest {
+						highest = tier
+					}
+				}
+				if lowest < 0 || highest < lowest {
+					lowest = highest
+				}
+			}
+			if element.Tier != lowest+1 {
+				t.Fatalf("seed %d: %s has tier %d, its easiest recipe needs tier %d", seed, name, element.Tier, lowest)
+			}
+		}
+
+		reachable := graph.NewElementGraph(elements).ReachableFrom(BaseElements)
+		if len(reachable) != len(elements) {
+			t.Fatalf("seed %d: only %d of %d elements are reachable", seed, len(reachable), len(elements))
+		}
+	}
+}
+
+func TestGenerateCycles(t *testing.T) {
+	log.SetOutput(io.Discard)
+	defer log.SetOutput(os.Stderr)
+
+	config := DefaultConfig()
+	config.Cycles = 0.5
+	elements := generate(t, config)
+
+	_, report := utils.ValidateRecipes(elements, utils.DefaultValidationPolicy())
+	if len(report.Rejected) == 0 {
+		t.Fatal("expected cycle recipes to break tier order")
+	}
+	for _, rejected := range report.Rejected {
+		if rejected.Policy != utils.PolicyTierOrder {
+			t.Fatalf("unexpected rejection %+v", rejected)
+		}
+		for _, ingredient := range rejected.Ingredients {
+			if ingredient == rejected.Element {
+				t.Fatalf("%s is used in its own recipe", rejected.Element)
+			}
+		}
+	}
+}
+
+func TestWriteCanBeLoaded(t *testing.T) {
+	log.SetOutput(io.Discard)
+	defer log.SetOutput(os.Stderr)
+
+	config := Config{Elements: 50, MinRecipes: 1, MaxRecipes: 3, FanIn: 2, Cycles: 0.2, Seed: 9}
+	generated, err := Generate(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := Write(&buf, generated); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "synthetic.json")
+	if err := os.WriteFile(path, buf.Bytes(), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	dataset, err := internal.LoadDataset(path, utils.UnrestrictedPolicy())
+	if err != nil {
+		t.Fatalf("LoadDataset: %v", err)
+	}
+	// validasi mengubah resep kosong dari nil jadi slice kosong, jadi yang
+	// dibandingkan bentuk JSON-nya
+	loaded, _ := json.Marshal(dataset.Elements)
+	want, _ := json.Marshal(Map(generated))
+	if !bytes.Equal(loaded, want) {
+		t.Fatal("loaded dataset differs from the generated graph")
+	}
+}
+
+func TestGenerateRejectsInvalidConfig(t *testing.T) {
+	valid := Config{Elements: 20, MinRecipes: 1, MaxRecipes: 2, FanIn: 2}
+	tests := map[string]func(c *Config){
+		"too few elements": func(c *Config) { c.Elements = 4 },
+		"too many tiers":   func(c *Config) { c.Tiers = 17 },
+		"no recipes":       func(c *Config) { c.MinRecipes = 0 },
+		"min above max":    func(c *Config) { c.MinRecipes = 3 },
+		"no fan-in":        func(c *Config) { c.FanIn = 0 },
+		"fan-in 1":         func(c *Config) { c.FanIn = 1 },
+		"fan-in 3":         func(c *Config) { c.FanIn = 3 },
+		"cycles above 1":   func(c *Config) { c.Cycles = 1.5 },
+	}
+	for name, mutate := range tests {
+		t.Run(name, func(t *testing.T) {
+			config := valid
+			mutate(&config)
+			if _, err := Generate(config); err == nil {
+				t.Fatalf("expected an error for %+v", config)
+			}
+		})
+	}
+}
@@ -3,27 +3,42 @@ package main
 import (
 	"backend/api"
 	"backend/internal"
+	"backend/utils"
 	"log"
 	"net/http"
+	"os"
 )
 
 func main() {
-	elements, elementGraph, err := internal.LoadElements()
+	policy, err := utils.PolicyFromEnv()
+	if err != nil {
+		log.Fatalf("Invalid validation policy: %v", err)
+	}
+
+	datasetPath := os.Getenv("ELEMENTS_FILE")
+	if datasetPath == "" {
+		datasetPath = "elements.json"
+	}
+
+	dataset, err := internal.LoadDataset(datasetPath, policy)
 	if err != nil {
 		log.Fatalf("Failed to load elements: %v", err)
 	}
+	elements, elementGraph := dataset.Elements, dataset.Graph
 
 	log.Printf("Successfully loaded %d elements", len(elements))
 	log.Printf("Graph built with %d nodes and %d base elements",
 		len(elementGraph.Nodes), len(elementGraph.BaseElements))
+	log.Printf("Active validation policies: %v", dataset.Policy.EnabledPolicies())
 
-	handler := api.NewHandler(elements)
+	handler := api.NewHandler(dataset)
 
 	corsMiddleware := func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			w.Header().Set("Access-Control-Allow-Origin", "*")
-			w.Header().Set("Access-Control-Allow-Methods", "GET, POST, OPTIONS")
+			w.Header().Set("Access-Control-Allow-Methods", "GET, POST, DELETE, OPTIONS")
 			w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
+			w.Header().Set("Access-Control-Expose-Headers", "X-Total-Count, Location")
 
 			if r.Method == "OPTIONS" {
 				w.WriteHeader(http.StatusOK)
@@ -38,6 +53,17 @@ func main() {
 
 	//cors middleware ke semua route
 	mux.Handle("/api/elements/", corsMiddleware(http.HandlerFunc(handler.HandleGetElements)))
+	mux.Handle("/api/stats", corsMiddleware(http.HandlerFunc(handler.HandleGetStats)))
+	mux.Handle("/api/analysis/importance", corsMiddleware(http.HandlerFunc(handler.HandleImportance)))
+	mux.Handle("/api/analysis/importance/", corsMiddleware(http.HandlerFunc(handler.HandleImportance)))
+	mux.Handle("/api/complexity", corsMiddleware(http.HandlerFunc(handler.HandleComplexity)))
+	mux.Handle("/api/complexity/", corsMiddleware(http.HandlerFunc(handler.HandleComplexity)))
+	mux.Handle("/api/search/batch", corsMiddleware(http.HandlerFunc(handler.HandleBatchSearch)))
+	mux.Handle("/api/compare/", corsMiddleware(http.HandlerFunc(handler.HandleCompare)))
+	mux.Handle("/api/jobs", corsMiddleware(http.HandlerFunc(handler.HandleJobs)))
+	mux.Handle("/api/jobs/", corsMiddleware(http.HandlerFunc(handler.HandleJobs)))
+	mux.Handle("/api/recordings", corsMiddleware(http.HandlerFunc(handler.HandleRecordings)))
+	mux.Handle("/api/recordings/", corsMiddleware(http.HandlerFunc(handler.HandleRecordings)))
 	mux.Handle("/api/bfs-tree/", corsMiddleware(http.HandlerFunc(handler.HandleBFSTree)))
 	mux.Handle("/api/dfs-tree/", corsMiddleware(http.HandlerFunc(handler.HandleDFSTree)))
 	mux.Handle("/api/bidirectional/", corsMiddleware(http.HandlerFunc(handler.HandleBidirectionalSearch)))
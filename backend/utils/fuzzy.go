@@ -0,0 +1,142 @@
+package utils
+
+import (
+	"sort"
+	"strings"
+)
+
+// NameMatch adalah hasil pencocokan nama elemen dengan query
+type NameMatch struct {
+	Name     string  `json:"name"`
+	Score    float64 `json:"score"`
+	Distance int     `json:"distance"`
+}
+
+// MinMatchScore adalah skor minimum supaya nama dianggap mirip dengan query
+const MinMatchScore = 0.35
+
+// RankNames mengurutkan names berdasarkan kemiripan dengan query (case-insensitive,
+// toleran typo lewat edit distance dan trigram). Nama dengan skor di bawah
+// MinMatchScore dibuang.
+func RankNames(query string, names []string, limit int) []NameMatch {
+	query = strings.ToLower(strings.TrimSpace(query))
+	if query == "" {
+		return []NameMatch{}
+	}
+
+	queryTrigrams := trigrams(query)
+	matches := make([]NameMatch, 0)
+
+	for _, name := range names {
+		lowerName := strings.ToLower(name)
+		distance := EditDistance(query, lowerName)
+		score := matchScore(query, lowerName, distance, queryTrigrams)
+
+		if score >= MinMatchScore {
+			matches = append(matches, NameMatch{Name: name, Score: score, Distance: distance})
+		}
+	}
+
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].Score != matches[j].Score {
+			return matches[i].Score > matches[j].Score
+		}
+		if matches[i].Distance != matches[j].Distance {
+			return matches[i].Distance < matches[j].Distance
+		}
+		return matches[i].Name < matches[j].Name
+	})
+
+	if limit > 0 && len(matches) > limit {
+		matches = matches[:limit]
+	}
+
+	return matches
+}
+
+func matchScore(query, name string, distance int, queryTrigrams map[string]bool) float64 {
+	switch {
+	case query == name:
+		return 1
+	case strings.HasPrefix(name, query):
+		return 0.9 + 0.05*float64(len(query))/float64(len(name))
+	case strings.Contains(name, query):
+		return 0.8 + 0.05*float64(len(query))/float64(len(name))
+	}
+
+	longest := len([]rune(query))
+	if n := len([]rune(name)); n > longest {
+		longest = n
+	}
+	editScore := 1 - float64(distance)/float64(longest)
+
+	trigramScore := jaccard(queryTrigrams, trigrams(name))
+
+	score := editScore
+	if trigramScore > score {
+		score = trigramScore
+	}
+	// masih di bawah skor prefix/substring biar yang persis lebih dulu
+	return score * 0.8
+}
+
+// EditDistance menghitung jarak Damerau-Levenshtein (optimal string alignment)
+// antara dua string per rune, jadi huruf yang ketuker ("fier") dihitung satu edit.
+func EditDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	if len(ra) == 0 {
+		return len(rb)
+	}
+	if len(rb) == 0 {
+		return len(ra)
+	}
+
+	prevPrev := make([]int, len(rb)+1)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+
+			if i > 1 && j > 1 && ra[i-1] == rb[j-2] && ra[i-2] == rb[j-1] {
+				curr[j] = min(curr[j], prevPrev[j-2]+1)
+			}
+		}
+		prevPrev, prev, curr = prev, curr, prevPrev
+	}
+
+	return prev[len(rb)]
+}
+
+func trigrams(s string) map[string]bool {
+	padded := []rune("  " + s + " ")
+	result := make(map[string]bool, len(padded))
+	for i := 0; i+3 <= len(padded); i++ {
+		result[string(padded[i:i+3])] = true
+	}
+	return result
+}
+
+func jaccard(a, b map[string]bool) float64 {
+	if len(a) == 0 && len(b) == 0 {
+		return 0
+	}
+
+	intersection := 0
+	for gram := range a {
+		if b[gram] {
+			intersection++
+		}
+	}
+
+	return float64(intersection) / float64(len(a)+len(b)-intersection)
+}
@@ -0,0 +1,95 @@
+package utils
+
+import "testing"
+
+func TestEditDistance(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"fire", "", 4},
+		{"", "fire", 4},
+		{"fire", "fire", 0},
+		{"kitten", "sitting", 3},
+		// huruf yang ketuker dihitung satu edit
+		{"fier", "fire", 1},
+		{"ab", "ba", 1},
+		{"wtaer", "water", 1},
+		// optimal string alignment: substring yang sudah ditukar tidak diedit lagi
+		{"ca", "abc", 3},
+		// dihitung per rune, bukan per byte
+		{"café", "cafe", 1},
+		{"naïve", "naive", 1},
+		{"日本", "本日", 1},
+		{"日本語", "日本", 1},
+	}
+
+	for _, test := range tests {
+		if got := EditDistance(test.a, test.b); got != test.want {
+			t.Errorf("EditDistance(%q, %q) = %d, expected %d", test.a, test.b, got, test.want)
+		}
+		if got := EditDistance(test.b, test.a); got != test.want {
+			t.Errorf("EditDistance(%q, %q) = %d, expected %d", test.b, test.a, got, test.want)
+		}
+	}
+}
+
+func TestRankNames(t *testing.T) {
+	names := []string{"Water", "Fire", "Firework", "Wildfire", "Fireplace", "Fish", "Earth", "Sand"}
+
+	tests := []struct {
+		query string
+		limit int
+		want  []string
+	}{
+		// persis, lalu prefix (yang lebih pendek dulu), substring, baru typo
+		{"fire", 0, []string{"Fire", "Firework", "Fireplace", "Wildfire", "Fish"}},
+		{"  FIRE ", 2, []string{"Fire", "Firework"}},
+		// typo: huruf ketuker atau kurang satu
+		{"fier", 3, []string{"Fire", "Fish", "Firework"}},
+		{"watr", 0, []string{"Water"}},
+		// tidak ada yang cukup mirip
+		{"xyz", 0, []string{}},
+		{"", 0, []string{}},
+	}
+
+	for _, test := range tests {
+		matches := RankNames(test.query, names, test.limit)
+		got := make([]string, len(matches))
+		for i, match := range matches {
+			got[i] = match.Name
+			if match.Score < MinMatchScore {
+				t.Errorf("RankNames(%q) kept %s with score %g below the threshold", test.query, match.Name, match.Score)
+			}
+			if i > 0 && match.Score > matches[i-1].Score {
+				t.Errorf("RankNames(%q) is not sorted by score: %v", test.query, matches)
+			}
+		}
+		if len(got) != len(test.want) {
+			t.Errorf("RankNames(%q, %d) = %v, expected %v", test.query, test.limit, got, test.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != test.want[i] {
+				t.Errorf("RankNames(%q, %d) = %v, expected %v", test.query, test.limit, got, test.want)
+				break
+			}
+		}
+	}
+}
+
+// nama dengan skor tepat di bawah threshold dibuang, yang di atasnya tetap ada
+func TestRankNamesThreshold(t *testing.T) {
+	query := "abcdefghij"
+	// edit score 1 - d/10, dikali 0.8: 6 edit -> 0.32, 5 edit -> 0.40
+	below, above := "abcdXXXXXX", "abcdeXXXXX"
+
+	matches := RankNames(query, []string{below, above}, 0)
+	if len(matches) != 1 || matches[0].Name != above {
+		t.Fatalf("expected only %s above the threshold, got %v", above, matches)
+	}
+	if matches[0].Distance != 5 {
+		t.Errorf("expected distance 5, got %d", matches[0].Distance)
+	}
+}
@@ -0,0 +1,422 @@
+package utils
+
+import (
+	"backend/model"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+)
+
+// BuildTrees mengubah path hasil pencarian jadi pohon resep yang unik, paling
+// banyak limit pohon (limit <= 0 berarti semua). Dipakai handler API, CLI dan
+// differential test supaya bentuk pohonnya sama persis.
+func BuildTrees(elements map[string]model.Element, paths [][]model.Node, elementName string, limit int) []map[string]interface{} {
+	baseElements := []string{"Water", "Fire", "Earth", "Air"}
+	trees := make([]map[string]interface{}, 0, len(paths))
+	uniqueSignatures := make(map[string]bool)
+
+	for _, path := range paths {
+		tree := ConvertPathToTree(path, elementName, elements, baseElements)
+		if tree == nil {
+			continue
+		}
+		EnsureIngredientsExpanded(tree, elements, baseElements, make(map[string]bool))
+
+		signature := DetailedTreeSignature(tree)
+		if uniqueSignatures[signature] {
+			continue
+		}
+		uniqueSignatures[signature] = true
+		trees = append(trees, tree)
+
+		if limit > 0 && len(trees) >= limit {
+			break
+		}
+	}
+
+	return trees
+}
+
+// ConvertPathToTree mengubah satu path hasil pencarian jadi pohon resep untuk
+// targetElement. Elemen yang tidak ada di path memakai resep pertamanya.
+func ConvertPathToTree(path []model.Node, targetElement string, elements map[string]model.Element, baseElements []string) map[string]interface{} {
+	if len(path) == 0 {
+		return nil
+	}
+
+	var targetNode *model.Node
+	for i := range path {
+		if path[i].Element == targetElement {
+			targetNode = &path[i]
+			break
+		}
+	}
+
+	if targetNode == nil {
+		return nil
+	}
+
+	nodeMap := make(map[string]*model.Node)
+	positionNodeMap := make(map[string]map[int]*model.Node) // Track nodes by position
+
+	for i := range path {
+		nodeMap[path[i].Element] = &path[i]
+
+		if path[i].Position != 0 {
+			elemKey := path[i].Element
+			if positionNodeMap[elemKey] == nil {
+				positionNodeMap[elemKey] = make(map[int]*model.Node)
+			}
+			positionNodeMap[elemKey][path[i].Position] = &path[i]
+		}
+	}
+
+	processedInBranch := make(map[string]bool)
+	validityCache := make(map[string]bool)
+
+	isElementMakeable := func(element string) bool {
+		for _, base := range baseElements {
+			if element == base {
+				return true
+			}
+		}
+
+		if result, ok := validityCache[element]; ok {
+			return result
+		}
+
+		elemData, exists := elements[element]
+		if !exists {
+			validityCache[element] = false
+			return false
+		}
+
+		if len(elemData.Recipes) == 0 {
+			validityCache[element] = false
+			return false
+		}
+
+		validityCache[element] = true
+		return true
+	}
+
+	var buildTree func(element string, depth int) map[string]interface{}
+	buildTree = func(element string, depth int) map[string]interface{} {
+		if processedInBranch[element] {
+			return map[string]interface{}{
+				"name":                element,
+				"isCircularReference": true,
+				"ingredients":         []interface{}{},
+			}
+		}
+
+		if posMap, exists := positionNodeMap[element]; exists && len(posMap) > 0 {
+			log.Printf("DEBUG: Using position-specific recipe for %s", element)
+		}
+
+		if !isElementMakeable(element) && !processedInBranch[element] {
+			elemData, exists := elements[element]
+			if exists {
+				return map[string]interface{}{
+					"name":        element,
+					"imagePath":   elemData.ImagePath,
+					"unmakeable":  true,
+					"ingredients": []interface{}{},
+				}
+			}
+			return nil
+		}
+
+		processedInBranch[element] = true
+		defer func() {
+			delete(processedInBranch, element)
+		}()
+
+		node, found := nodeMap[element]
+		if !found {
+			elemData, exists := elements[element]
+			if !exists {
+				return nil
+			}
+
+			isBase := false
+			for _, base := range baseElements {
+				if element == base {
+					isBase = true
+					break
+				}
+			}
+
+			treeNode := map[string]interface{}{
+				"name":          element,
+				"imagePath":     elemData.ImagePath,
+				"isBaseElement": isBase,
+				"ingredients":   []interface{}{},
+			}
+
+			if !isBase && depth < 10 && len(elemData.Recipes) > 0 {
+				recipe := elemData.Recipes[0]
+				for _, ingredient := range recipe.Ingredients {
+					subtree := buildTree(ingredient, depth+1)
+					if subtree != nil {
+						treeNode["ingredients"] = append(treeNode["ingredients"].([]interface{}), subtree)
+					}
+				}
+			}
+
+			return treeNode
+		}
+
+		isBase := false
+		for _, base := range baseElements {
+			if element == base {
+				isBase = true
+				break
+			}
+		}
+
+		treeNode := map[string]interface{}{
+			"name":        element,
+			"imagePath":   node.ImagePath,
+			"ingredients": []interface{}{},
+		}
+
+		if isBase {
+			treeNode["isBaseElement"] = true
+			return treeNode
+		}
+
+		ingredients := node.Ingredients
+		if len(ingredients) == 0 {
+			if elemData, exists := elements[element]; exists && len(elemData.Recipes) > 0 {
+				ingredients = elemData.Recipes[0].Ingredients
+			}
+		}
+
+		if depth < 10 {
+			for _, ingredient := range ingredients {
+				subtree := buildTree(ingredient, depth+1)
+				if subtree != nil {
+					for i := 0; i < len(ingredients); i++ {
+						if ingredients[i] == ingredient && i > 0 {
+							subtree["pathIndex"] = i
+							subtree["ingredientIndex"] = i
+							break
+						}
+					}
+					treeNode["ingredients"] = append(treeNode["ingredients"].([]interface{}), subtree)
+				}
+			}
+		}
+
+		return treeNode
+	}
+
+	return buildTree(targetElement, 0)
+}
+
+// EnsureIngredientsExpanded mengembangkan node yang belum punya ingredient
+// sampai elemen dasar. Hasilnya false kalo ada cabang yang tidak bisa dibuat.
+func EnsureIngredientsExpanded(tree map[string]interface{}, elements map[string]model.Element, baseElements []string, visited map[string]bool) bool {
+	if tree == nil {
+		return false
+	}
+
+	elementName, ok := tree["name"].(string)
+	if !ok || visited[elementName] {
+		return false
+	}
+
+	visited[elementName] = true
+	defer delete(visited, elementName)
+
+	isBase := false
+	for _, base := range baseElements {
+		if elementName == base {
+			isBase = true
+			break
+		}
+	}
+
+	if isBase {
+		tree["isBaseElement"] = true
+		return true
+	}
+
+	// Check if this element is makeable
+	elemData, exists := elements[elementName]
+	if !exists || len(elemData.Recipes) == 0 {
+		tree["unmakeable"] = true
+		return false
+	}
+
+	ingredients, ok := tree["ingredients"].([]interface{})
+	allIngredientsValid := true
+
+	if !ok || len(ingredients) == 0 {
+		// coba resep satu per satu, resep pertama bisa butuh elemen yang tidak
+		// bisa dibuat (Leaf: Tree + Wind, padahal Tree tidak punya resep valid)
+		// atau berputar ke ancestor. Kalo tidak ada yang valid, pakai resep
+		// pertama seperti sebelumnya.
+		var firstIngredients []interface{}
+		for i, recipe := range elemData.Recipes {
+			newIngredients, recipeValid := expandRecipe(recipe, elements, baseElements, visited)
+			if recipeValid {
+				tree["ingredients"] = newIngredients
+				return true
+			}
+			if i == 0 {
+				firstIngredients = newIngredients
+			}
+		}
+
+		tree["ingredients"] = firstIngredients
+		allIngredientsValid = false
+	} else {
+		for _, ing := range ingredients {
+			if ingTree, ok := ing.(map[string]interface{}); ok {
+				ingValid := EnsureIngredientsExpanded(ingTree, elements, baseElements, visited)
+				if !ingValid {
+					allIngredientsValid = false
+				}
+			}
+		}
+
+		// resep dari path buntu, ganti dengan resep lain yang bisa dibuat
+		if !allIngredientsValid {
+			for _, recipe := range elemData.Recipes {
+				if newIngredients, recipeValid := expandRecipe(recipe, elements, baseElements, visited); recipeValid {
+					tree["ingredients"] = newIngredients
+					return true
+				}
+			}
+		}
+	}
+
+	return allIngredientsValid
+}
+
+// expandRecipe membuat node ingredient untuk satu resep dan mengembangkannya
+// sampai elemen dasar, valid false kalo ada ingredient yang tidak bisa dibuat
+func expandRecipe(recipe model.ElementRecipe, elements map[string]model.Element, baseElements []string, visited map[string]bool) ([]interface{}, bool) {
+	valid := true
+	newIngredients := make([]interface{}, 0, len(recipe.Ingredients))
+
+	for _, ingName := range recipe.Ingredients {
+		ingIsBase := false
+		for _, base := range baseElements {
+			if ingName == base {
+				ingIsBase = true
+				break
+			}
+		}
+
+		ingData, ingExists := elements[ingName]
+		if !ingExists {
+			valid = false
+			continue
+		}
+
+		ingTree := map[string]interface{}{
+			"name":          ingName,
+			"imagePath":     ingData.ImagePath,
+			"isBaseElement": ingIsBase,
+			"ingredients":   []interface{}{},
+		}
+
+		if !ingIsBase && !EnsureIngredientsExpanded(ingTree, elements, baseElements, visited) {
+			valid = false
+		}
+
+		newIngredients = append(newIngredients, ingTree)
+	}
+
+	return newIngredients, valid
+}
+
+// IsTreeFullyMakeable memeriksa semua daun pohon adalah elemen dasar dan
+// tidak ada node yang ditandai unmakeable
+func IsTreeFullyMakeable(tree map[string]interface{}) bool {
+	if unmakeable, ok := tree["unmakeable"].(bool); ok && unmakeable {
+		elementName, _ := tree["name"].(string)
+		log.Printf("DEBUG: Tree node %s is unmakeable, rejecting tree", elementName)
+		return false
+	}
+
+	elementName, _ := tree["name"].(string)
+	ingredients, hasIngredients := tree["ingredients"].([]interface{})
+	isBase, hasBase := tree["isBaseElement"].(bool)
+
+	if (!hasBase || !isBase) && (!hasIngredients || len(ingredients) == 0) {
+		log.Printf("DEBUG: Non-base element %s has no ingredients, marking as unmakeable", elementName)
+		tree["unmakeable"] = true
+		return false
+	}
+
+	if hasIngredients {
+		for _, ing := range ingredients {
+			ingredient, ok := ing.(map[string]interface{})
+			if !ok {
+				continue
+			}
+
+			if !IsTreeFullyMakeable(ingredient) {
+				log.Printf("DEBUG: Tree node %s has unmakeable ingredient, rejecting tree", elementName)
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// DetailedTreeSignature membuat signature pohon untuk membuang pohon yang
+// sama, urutan ingredient diabaikan kecuali ingredient kembar
+func DetailedTreeSignature(tree map[string]interface{}) string {
+	var sb strings.Builder
+	sb.WriteString(tree["name"].(string))
+	if pos, ok := tree["position"].(int); ok && pos > 0 {
+		sb.WriteString(fmt.Sprintf("#%d", pos))
+	}
+
+	sb.WriteString(":")
+
+	ingredients, ok := tree["ingredients"].([]interface{})
+	if !ok || len(ingredients) == 0 {
+		return sb.String() + "[]"
+	}
+
+	elementName, _ := tree["name"].(string)
+	preserveOrder := (elementName == "Planet" || elementName == "Continent") ||
+		(len(ingredients) >= 2 && ingredients[0].(map[string]interface{})["name"] == ingredients[1].(map[string]interface{})["name"])
+
+	ingredientSignatures := make([]string, 0, len(ingredients))
+
+	for i, ing := range ingredients {
+		ingredient, ok := ing.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		ingredientSig := DetailedTreeSignature(ingredient)
+
+		if i > 0 && ingredient["name"] == ingredients[i-1].(map[string]interface{})["name"] {
+			ingredientSig = fmt.Sprintf("%d:%s", i, ingredientSig)
+		} else if preserveOrder {
+			ingredientSig = fmt.Sprintf("%d:%s", i, ingredientSig)
+		}
+
+		ingredientSignatures = append(ingredientSignatures, ingredientSig)
+	}
+
+	if !preserveOrder && len(ingredientSignatures) > 1 {
+		sort.Strings(ingredientSignatures)
+	}
+
+	sb.WriteString("[")
+	sb.WriteString(strings.Join(ingredientSignatures, ","))
+	sb.WriteString("]")
+
+	return sb.String()
+}
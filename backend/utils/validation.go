@@ -2,15 +2,176 @@ package utils
 
 import (
 	"backend/model"
+	"encoding/json"
+	"fmt"
 	"log"
+	"os"
+	"slices"
+	"strings"
 )
 
+// nama-nama policy validasi resep
+const (
+	PolicyTimeExclusion   = "time-exclusion"
+	PolicyTierOrder       = "tier-order"
+	PolicyCustomExclusion = "custom-exclusion"
+)
+
+// ValidationPolicy menentukan aturan mana yang dipakai untuk membuang resep.
+// Default-nya sesuai spek tubes: resep yang mengandung Time dibuang dan
+// ingredient harus punya tier lebih kecil dari hasilnya.
+type ValidationPolicy struct {
+	ExcludeTime      bool     `json:"excludeTime"`
+	EnforceTierOrder bool     `json:"enforceTierOrder"`
+	ExcludedElements []string `json:"excludedElements,omitempty"`
+}
+
+// RejectedRecipe adalah resep yang dibuang beserta policy yang membuangnya.
+type RejectedRecipe struct {
+	Element     string   `json:"element"`
+	Ingredients []string `json:"ingredients"`
+	Policy      string   `json:"policy"`
+	Ingredient  string   `json:"ingredient"`
+}
+
+// ValidationReport merangkum hasil validasi satu dataset.
+type ValidationReport struct {
+	TotalRecipes int              `json:"totalRecipes"`
+	Rejected     []RejectedRecipe `json:"rejected"`
+}
+
+func DefaultValidationPolicy() ValidationPolicy {
+	return ValidationPolicy{
+		ExcludeTime:      true,
+		EnforceTierOrder: true,
+	}
+}
+
+// UnrestrictedPolicy tidak membuang resep apa pun, buat eksplor graf resep lengkap.
+func UnrestrictedPolicy() ValidationPolicy {
+	return ValidationPolicy{}
+}
+
+// EnabledPolicies mengembalikan nama policy yang aktif.
+func (p ValidationPolicy) EnabledPolicies() []string {
+	names := make([]string, 0, 3)
+	if p.ExcludeTime {
+		names = append(names, PolicyTimeExclusion)
+	}
+	if p.EnforceTierOrder {
+		names = append(names, PolicyTierOrder)
+	}
+	if len(p.ExcludedElements) > 0 {
+		names = append(names, PolicyCustomExclusion)
+	}
+	return names
+}
+
+// ParsePolicyNames membuat policy dari daftar nama yang dipisah koma,
+// misal "time-exclusion,tier-order". "none" mematikan semua policy.
+// Elemen untuk custom-exclusion diambil dari excluded, jadi custom-exclusion
+// harus disebut kalo excluded tidak kosong dan sebaliknya.
+func ParsePolicyNames(list string, excluded []string) (ValidationPolicy, error) {
+	policy := UnrestrictedPolicy()
+	customExclusion := false
+
+	for _, name := range strings.Split(list, ",") {
+		name = strings.TrimSpace(strings.ToLower(name))
+		switch name {
+		case "", "none":
+		case PolicyTimeExclusion:
+			policy.ExcludeTime = true
+		case PolicyTierOrder:
+			policy.EnforceTierOrder = true
+		case PolicyCustomExclusion:
+			customExclusion = true
+		default:
+			return policy, fmt.Errorf("unknown validation policy %q", name)
+		}
+	}
+
+	switch {
+	case customExclusion && len(excluded) == 0:
+		return policy, fmt.Errorf("%s needs a list of excluded elements (RECIPE_EXCLUDE)", PolicyCustomExclusion)
+	case !customExclusion && len(excluded) > 0:
+		return policy, fmt.Errorf("excluded elements %v given but %s is not in the policy list", excluded, PolicyCustomExclusion)
+	}
+	policy.ExcludedElements = excluded
+
+	return policy, nil
+}
+
+// PolicyFromEnv membaca policy dari RECIPE_POLICIES dan RECIPE_EXCLUDE.
+// Kalo RECIPE_POLICIES tidak di-set dipakai policy default, ditambah
+// custom-exclusion kalo RECIPE_EXCLUDE ada isinya.
+func PolicyFromEnv() (ValidationPolicy, error) {
+	excluded := splitNames(os.Getenv("RECIPE_EXCLUDE"))
+
+	if list, ok := os.LookupEnv("RECIPE_POLICIES"); ok {
+		return ParsePolicyNames(list, excluded)
+	}
+
+	policy := DefaultValidationPolicy()
+	policy.ExcludedElements = excluded
+	return policy, nil
+}
+
+func splitNames(list string) []string {
+	var names []string
+	for _, name := range strings.Split(list, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// LoadValidationPolicy membaca policy dari file JSON. Field yang tidak ada
+// di file tetap memakai nilai dari fallback. excludedElements di file tidak
+// menimpa daftar dari fallback tapi digabung, jadi elemen dari RECIPE_EXCLUDE
+// tetap dibuang.
+func LoadValidationPolicy(path string, fallback ValidationPolicy) (ValidationPolicy, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fallback, err
+	}
+
+	policy := fallback
+	policy.ExcludedElements = nil
+	if err := json.Unmarshal(data, &policy); err != nil {
+		return fallback, fmt.Errorf("invalid policy file %s: %w", path, err)
+	}
+
+	merged := append([]string{}, fallback.ExcludedElements...)
+	for _, name := range policy.ExcludedElements {
+		if !slices.Contains(merged, name) {
+			merged = append(merged, name)
+		}
+	}
+	if len(merged) == 0 {
+		merged = nil
+	}
+	policy.ExcludedElements = merged
+
+	return policy, nil
+}
+
 func ValidateRecipeTiers(elements map[string]model.Element) map[string]model.Element {
-	log.Println("Starting tier validation for all recipes...")
+	validated, _ := ValidateRecipes(elements, DefaultValidationPolicy())
+	return validated
+}
+
+// ValidateRecipes membuang resep yang melanggar policy dan mencatat alasannya.
+func ValidateRecipes(elements map[string]model.Element, policy ValidationPolicy) (map[string]model.Element, ValidationReport) {
+	log.Printf("Starting recipe validation with policies: %v", policy.EnabledPolicies())
 
-	totalRecipes := 0
-	invalidRecipes := 0
-	timeExcludedRecipes := 0
+	report := ValidationReport{Rejected: make([]RejectedRecipe, 0)}
+	rejectedByPolicy := make(map[string]int)
+
+	excluded := make(map[string]bool, len(policy.ExcludedElements))
+	for _, name := range policy.ExcludedElements {
+		excluded[name] = true
+	}
 
 	validatedElements := make(map[string]model.Element)
 
@@ -18,35 +179,21 @@ func ValidateRecipeTiers(elements map[string]model.Element) map[string]model.Ele
 		validRecipes := make([]model.ElementRecipe, 0)
 
 		for _, recipe := range element.Recipes {
-			totalRecipes++
-			valid := true
-
-			for _, ingredientName := range recipe.Ingredients {
-				if ingredientName == "Time" {
-					log.Printf("Excluding recipe for '%s' because it contains the Time element", name)
-					valid = false
-					break
-				}
-
-				ingredient, exists := elements[ingredientName]
-				if !exists {
-					log.Printf("Warning: Ingredient '%s' for '%s' not found in element database",
-						ingredientName, name)
-					continue
-				}
-
-				if ingredient.Tier >= element.Tier {
-					log.Printf("Invalid recipe: %s (tier %d) + others → %s (tier %d)",
-						ingredientName, ingredient.Tier, name, element.Tier)
-					valid = false
-					invalidRecipes++
-					break
-				}
-			}
+			report.TotalRecipes++
+			rejectedBy, culprit := checkRecipe(name, element, recipe, elements, policy, excluded)
 
-			if valid {
+			if rejectedBy == "" {
 				validRecipes = append(validRecipes, recipe)
+				continue
 			}
+
+			rejectedByPolicy[rejectedBy]++
+			report.Rejected = append(report.Rejected, RejectedRecipe{
+				Element:     name,
+				Ingredients: recipe.Ingredients,
+				Policy:      rejectedBy,
+				Ingredient:  culprit,
+			})
 		}
 
 		elementCopy := element
@@ -54,11 +201,48 @@ func ValidateRecipeTiers(elements map[string]model.Element) map[string]model.Ele
 		validatedElements[name] = elementCopy
 	}
 
-	log.Printf("Tier validation complete: Found %d invalid recipes out of %d total recipes",
-		invalidRecipes, totalRecipes)
-	log.Printf("Excluded %d recipes containing the Time element", timeExcludedRecipes)
+	log.Printf("Recipe validation complete: rejected %d out of %d total recipes",
+		len(report.Rejected), report.TotalRecipes)
+	for _, policyName := range []string{PolicyTimeExclusion, PolicyCustomExclusion, PolicyTierOrder} {
+		if rejectedByPolicy[policyName] > 0 {
+			log.Printf("  %s: %d recipes", policyName, rejectedByPolicy[policyName])
+		}
+	}
+
+	return validatedElements, report
+}
+
+// checkRecipe mengembalikan nama policy yang dilanggar dan ingredient penyebabnya,
+// atau string kosong kalo resepnya valid.
+func checkRecipe(name string, element model.Element, recipe model.ElementRecipe, elements map[string]model.Element, policy ValidationPolicy, excluded map[string]bool) (string, string) {
+	for _, ingredientName := range recipe.Ingredients {
+		if policy.ExcludeTime && ingredientName == "Time" {
+			return PolicyTimeExclusion, ingredientName
+		}
+
+		if excluded[ingredientName] {
+			return PolicyCustomExclusion, ingredientName
+		}
+	}
+
+	if !policy.EnforceTierOrder {
+		return "", ""
+	}
+
+	for _, ingredientName := range recipe.Ingredients {
+		ingredient, exists := elements[ingredientName]
+		if !exists {
+			log.Printf("Warning: Ingredient '%s' for '%s' not found in element database",
+				ingredientName, name)
+			continue
+		}
+
+		if ingredient.Tier >= element.Tier {
+			return PolicyTierOrder, ingredientName
+		}
+	}
 
-	return validatedElements
+	return "", ""
 }
 
 func IsBaseElementName(name string, baseElements []string) bool {
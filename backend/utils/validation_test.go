@@ -0,0 +1,226 @@
+package utils
+
+import (
+	"backend/model"
+	"errors"
+	"io"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func policyElements() map[string]model.Element {
+	recipe := func(ingredients ...string) model.ElementRecipe {
+		return model.ElementRecipe{Ingredients: ingredients}
+	}
+	return map[string]model.Element{
+		"Water": {Name: "Water"},
+		"Fire":  {Name: "Fire"},
+		"Earth": {Name: "Earth"},
+		"Air":   {Name: "Air"},
+		"Time":  {Name: "Time", Tier: 1, Recipes: []model.ElementRecipe{recipe("Water", "Air")}},
+		"Steam": {Name: "Steam", Tier: 1, Recipes: []model.ElementRecipe{recipe("Water", "Fire")}},
+		"Life":  {Name: "Life", Tier: 2, Recipes: []model.ElementRecipe{recipe("Steam", "Earth")}},
+		// resep kedua melanggar tier-order, resep ketiga memakai Time
+		"Cloud": {Name: "Cloud", Tier: 2, Recipes: []model.ElementRecipe{recipe("Steam", "Air"), recipe("Life", "Air"), recipe("Time", "Steam")}},
+		// memakai Life yang bisa dibuang lewat custom-exclusion
+		"Human": {Name: "Human", Tier: 3, Recipes: []model.ElementRecipe{recipe("Life", "Earth")}},
+	}
+}
+
+func TestValidateRecipes(t *testing.T) {
+	log.SetOutput(io.Discard)
+	defer log.SetOutput(os.Stderr)
+
+	tests := []struct {
+		name     string
+		policy   ValidationPolicy
+		rejected map[string]string
+	}{
+		{"none", UnrestrictedPolicy(), map[string]string{}},
+		{"default", DefaultValidationPolicy(), map[string]string{
+			"Cloud: Life + Air":   PolicyTierOrder,
+			"Cloud: Time + Steam": PolicyTimeExclusion,
+		}},
+		{"tier order only", ValidationPolicy{EnforceTierOrder: true}, map[string]string{
+			"Cloud: Life + Air": PolicyTierOrder,
+		}},
+		{"time only", ValidationPolicy{ExcludeTime: true}, map[string]string{
+			"Cloud: Time + Steam": PolicyTimeExclusion,
+		}},
+		// custom-exclusion dicek sebelum tier-order
+		{"custom exclusion", ValidationPolicy{EnforceTierOrder: true, ExcludedElements: []string{"Life"}}, map[string]string{
+			"Cloud: Life + Air":   PolicyCustomExclusion,
+			"Human: Life + Earth": PolicyCustomExclusion,
+		}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			elements := policyElements()
+			validated, report := ValidateRecipes(elements, test.policy)
+
+			if report.TotalRecipes != 7 {
+				t.Errorf("expected 7 recipes checked, got %d", report.TotalRecipes)
+			}
+
+			rejected := make(map[string]string)
+			for _, r := range report.Rejected {
+				rejected[r.Element+": "+r.Ingredients[0]+" + "+r.Ingredients[1]] = r.Policy
+			}
+			if !reflect.DeepEqual(rejected, test.rejected) {
+				t.Errorf("rejected %v, expected %v", rejected, test.rejected)
+			}
+
+			for name, element := range elements {
+				if got, want := len(validated[name].Recipes), len(element.Recipes); got+countFor(test.rejected, name) != want {
+					t.Errorf("%s kept %d of %d recipes", name, got, want)
+				}
+			}
+		})
+	}
+}
+
+func countFor(rejected map[string]string, element string) int {
+	count := 0
+	for key := range rejected {
+		if strings.HasPrefix(key, element+":") {
+			count++
+		}
+	}
+	return count
+}
+
+func TestParsePolicyNames(t *testing.T) {
+	tests := []struct {
+		list     string
+		excluded []string
+		want     ValidationPolicy
+		wantErr  bool
+	}{
+		{list: "", want: UnrestrictedPolicy()},
+		{list: "none", want: UnrestrictedPolicy()},
+		{list: "time-exclusion,tier-order", want: DefaultValidationPolicy()},
+		{list: " Tier-Order , ", want: ValidationPolicy{EnforceTierOrder: true}},
+		{list: "tier-order,custom-exclusion", excluded: []string{"Life"},
+			want: ValidationPolicy{EnforceTierOrder: true, ExcludedElements: []string{"Life"}}},
+		{list: "tier-order,bogus", wantErr: true},
+		// custom-exclusion tanpa elemen, atau elemen tanpa custom-exclusion
+		{list: "custom-exclusion", wantErr: true},
+		{list: "none", excluded: []string{"Life"}, wantErr: true},
+	}
+
+	for _, test := range tests {
+		got, err := ParsePolicyNames(test.list, test.excluded)
+		if test.wantErr {
+			if err == nil {
+				t.Errorf("ParsePolicyNames(%q, %v) returned %+v, expected an error", test.list, test.excluded, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ParsePolicyNames(%q, %v): %v", test.list, test.excluded, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("ParsePolicyNames(%q, %v) = %+v, expected %+v", test.list, test.excluded, got, test.want)
+		}
+	}
+}
+
+func TestPolicyFromEnv(t *testing.T) {
+	tests := []struct {
+		name     string
+		policies *string
+		exclude  string
+		want     ValidationPolicy
+		wantErr  bool
+	}{
+		{name: "default", want: DefaultValidationPolicy()},
+		{name: "default with exclude", exclude: "Life, Time,",
+			want: ValidationPolicy{ExcludeTime: true, EnforceTierOrder: true, ExcludedElements: []string{"Life", "Time"}}},
+		{name: "custom exclusion named", policies: ptr("custom-exclusion"), exclude: "Life",
+			want: ValidationPolicy{ExcludedElements: []string{"Life"}}},
+		{name: "exclude without custom exclusion", policies: ptr("tier-order"), exclude: "Life", wantErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if test.policies != nil {
+				t.Setenv("RECIPE_POLICIES", *test.policies)
+			} else {
+				t.Setenv("RECIPE_POLICIES", "")
+				os.Unsetenv("RECIPE_POLICIES")
+			}
+			t.Setenv("RECIPE_EXCLUDE", test.exclude)
+
+			got, err := PolicyFromEnv()
+			if test.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error, got %+v", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("got %+v, expected %+v", got, test.want)
+			}
+		})
+	}
+}
+
+func ptr(s string) *string {
+	return &s
+}
+
+func TestLoadValidationPolicy(t *testing.T) {
+	fallback := ValidationPolicy{ExcludeTime: true, EnforceTierOrder: true, ExcludedElements: []string{"Life"}}
+
+	tests := []struct {
+		name    string
+		file    string
+		want    ValidationPolicy
+		wantErr bool
+	}{
+		// field yang tidak ada di file memakai fallback
+		{name: "partial", file: `{"excludeTime": false}`,
+			want: ValidationPolicy{EnforceTierOrder: true, ExcludedElements: []string{"Life"}}},
+		// excludedElements digabung, bukan ditimpa
+		{name: "merged exclusions", file: `{"excludedElements": ["Time", "Life"]}`,
+			want: ValidationPolicy{ExcludeTime: true, EnforceTierOrder: true, ExcludedElements: []string{"Life", "Time"}}},
+		{name: "invalid json", file: `{"excludeTime": "yes"}`, want: fallback, wantErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "elements.policy.json")
+			if err := os.WriteFile(path, []byte(test.file), 0o644); err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := LoadValidationPolicy(path, fallback)
+			if (err != nil) != test.wantErr {
+				t.Fatalf("unexpected error %v", err)
+			}
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("got %+v, expected %+v", got, test.want)
+			}
+		})
+	}
+
+	t.Run("missing file", func(t *testing.T) {
+		got, err := LoadValidationPolicy(filepath.Join(t.TempDir(), "missing.json"), fallback)
+		if !errors.Is(err, fs.ErrNotExist) {
+			t.Fatalf("expected a not-exist error, got %v", err)
+		}
+		if !reflect.DeepEqual(got, fallback) {
+			t.Errorf("got %+v, expected the fallback", got)
+		}
+	})
+}
@@ -0,0 +1,145 @@
+package utils
+
+import (
+	"backend/model"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// TreeIssue adalah satu alasan kenapa pohon resep tidak bisa dibuat
+type TreeIssue struct {
+	// nama elemen dari root sampai node yang bermasalah
+	Path    []string `json:"path"`
+	Problem string   `json:"problem"`
+}
+
+func (issue TreeIssue) String() string {
+	return strings.Join(issue.Path, " > ") + ": " + issue.Problem
+}
+
+// VerifyTree memeriksa bahwa pohon resep (bentuk map yang dikirim API) benar-benar
+// bisa dibuat dari dataset: root-nya target, setiap node dalam memakai salah satu
+// resep elemen tersebut, setiap daun adalah elemen dasar, dan tidak ada elemen
+// yang muncul lagi di bawah dirinya sendiri. Kalo target kosong, root tidak dicek.
+// Hasilnya kosong kalo pohon valid.
+func VerifyTree(tree map[string]interface{}, target string, elements map[string]model.Element, baseElements []string) []TreeIssue {
+	issues := make([]TreeIssue, 0)
+	if tree == nil {
+		return append(issues, TreeIssue{Path: []string{}, Problem: "tree is empty"})
+	}
+
+	if name, _ := tree["name"].(string); target != "" && name != target {
+		issues = append(issues, TreeIssue{Path: []string{name}, Problem: fmt.Sprintf("root is %q, expected %q", name, target)})
+	}
+
+	verifyNode(tree, nil, elements, baseElements, &issues)
+	return issues
+}
+
+func verifyNode(node map[string]interface{}, ancestors []string, elements map[string]model.Element, baseElements []string, issues *[]TreeIssue) {
+	name, ok := node["name"].(string)
+	path := append(append([]string{}, ancestors...), name)
+	report := func(format string, args ...interface{}) {
+		*issues = append(*issues, TreeIssue{Path: path, Problem: fmt.Sprintf(format, args...)})
+	}
+
+	if !ok || name == "" {
+		report("node has no name")
+		return
+	}
+	for _, ancestor := range ancestors {
+		if ancestor == name {
+			report("cycle, %s is needed to make itself", name)
+			return
+		}
+	}
+
+	element, exists := elements[name]
+	if !exists {
+		report("unknown element")
+		return
+	}
+
+	children, malformed := nodeChildren(node)
+	if malformed {
+		report("ingredients is not a list of nodes")
+		return
+	}
+
+	isBase := IsBaseElementName(name, baseElements)
+	if len(children) == 0 {
+		if !isBase {
+			report("leaf is not a base element")
+		}
+		return
+	}
+	if isBase {
+		report("base element has ingredients")
+		return
+	}
+
+	ingredients := make([]string, len(children))
+	for i, child := range children {
+		ingredients[i], _ = child["name"].(string)
+	}
+	if !hasRecipe(element, ingredients) {
+		report("%s is not a recipe for %s", strings.Join(ingredients, " + "), name)
+	}
+
+	for _, child := range children {
+		verifyNode(child, path, elements, baseElements, issues)
+	}
+}
+
+// nodeChildren membaca ingredients node, bisa berupa []interface{} (hasil
+// decode JSON atau buatan handler) atau []map[string]interface{}
+func nodeChildren(node map[string]interface{}) ([]map[string]interface{}, bool) {
+	switch ingredients := node["ingredients"].(type) {
+	case nil:
+		return nil, false
+	case []map[string]interface{}:
+		return ingredients, false
+	case []interface{}:
+		children := make([]map[string]interface{}, 0, len(ingredients))
+		for _, ingredient := range ingredients {
+			child, ok := ingredient.(map[string]interface{})
+			if !ok {
+				return nil, true
+			}
+			children = append(children, child)
+		}
+		return children, false
+	default:
+		return nil, true
+	}
+}
+
+// hasRecipe mengecek apakah ingredients (urutan bebas) adalah salah satu resep elemen
+func hasRecipe(element model.Element, ingredients []string) bool {
+	want := sortedCopy(ingredients)
+	for _, recipe := range element.Recipes {
+		if len(recipe.Ingredients) != len(want) {
+			continue
+		}
+		got := sortedCopy(recipe.Ingredients)
+		match := true
+		for i := range want {
+			if got[i] != want[i] {
+				match = false
+				break
+			}
+		}
+		if match {
+			return true
+		}
+	}
+	return false
+}
+
+func sortedCopy(names []string) []string {
+	sorted := make([]string, len(names))
+	copy(sorted, names)
+	sort.Strings(sorted)
+	return sorted
+}
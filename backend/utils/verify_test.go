@@ -0,0 +1,84 @@
+package utils
+
+import (
+	"backend/model"
+	"strings"
+	"testing"
+)
+
+var testBaseElements = []string{"Water", "Fire", "Earth", "Air"}
+
+func testElements() map[string]model.Element {
+	recipe := func(ingredients ...string) model.ElementRecipe {
+		return model.ElementRecipe{Ingredients: ingredients}
+	}
+	return map[string]model.Element{
+		"Water": {Name: "Water"},
+		"Fire":  {Name: "Fire"},
+		"Earth": {Name: "Earth"},
+		"Air":   {Name: "Air"},
+		"Steam": {Name: "Steam", Recipes: []model.ElementRecipe{recipe("Water", "Fire")}},
+		"Mud":   {Name: "Mud", Recipes: []model.ElementRecipe{recipe("Water", "Earth")}},
+		"Cloud": {Name: "Cloud", Recipes: []model.ElementRecipe{recipe("Steam", "Air"), recipe("Cloud", "Air")}},
+	}
+}
+
+func node(name string, ingredients ...map[string]interface{}) map[string]interface{} {
+	children := make([]interface{}, len(ingredients))
+	for i, ingredient := range ingredients {
+		children[i] = ingredient
+	}
+	return map[string]interface{}{"name": name, "ingredients": children}
+}
+
+func TestVerifyTree(t *testing.T) {
+	tests := []struct {
+		name   string
+		tree   map[string]interface{}
+		target string
+		// potongan pesan yang diharapkan, kosong berarti pohon valid
+		want string
+	}{
+		{"base element", node("Water"), "Water", ""},
+		{"valid", node("Cloud", node("Steam", node("Water"), node("Fire")), node("Air")), "Cloud", ""},
+		{"ingredient order does not matter", node("Steam", node("Fire"), node("Water")), "Steam", ""},
+		{"no target check", node("Steam", node("Water"), node("Fire")), "", ""},
+		{"wrong root", node("Steam", node("Water"), node("Fire")), "Cloud", `root is "Steam"`},
+		{"wrong recipe", node("Steam", node("Water"), node("Earth")), "Steam", "not a recipe for Steam"},
+		{"leaf not base", node("Cloud", node("Steam"), node("Air")), "Cloud", "leaf is not a base element"},
+		{"base with ingredients", node("Water", node("Fire"), node("Air")), "Water", "base element has ingredients"},
+		{"unknown element", node("Steam", node("Water"), node("Lava")), "Steam", "unknown element"},
+		{"cycle", node("Cloud", node("Cloud", node("Steam", node("Water"), node("Fire")), node("Air")), node("Air")), "Cloud", "cycle"},
+		{"missing name", node("Steam", node("Water"), map[string]interface{}{}), "Steam", "node has no name"},
+		{"empty tree", nil, "Steam", "tree is empty"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issues := VerifyTree(tt.tree, tt.target, testElements(), testBaseElements)
+			if tt.want == "" {
+				if len(issues) > 0 {
+					t.Fatalf("expected valid tree, got %v", issues)
+				}
+				return
+			}
+			for _, issue := range issues {
+				if strings.Contains(issue.Problem, tt.want) {
+					return
+				}
+			}
+			t.Fatalf("expected issue containing %q, got %v", tt.want, issues)
+		})
+	}
+}
+
+func TestVerifyTreeIssuePath(t *testing.T) {
+	tree := node("Cloud", node("Steam", node("Water"), node("Mud")), node("Air"))
+	issues := VerifyTree(tree, "Cloud", testElements(), testBaseElements)
+	if len(issues) == 0 {
+		t.Fatal("expected issues")
+	}
+	if got := issues[0].String(); !strings.HasPrefix(got, "Cloud > Steam: ") {
+		t.Fatalf("unexpected issue %q", got)
+	}
+}